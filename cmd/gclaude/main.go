@@ -6,14 +6,20 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"text/tabwriter"
 	"time"
 
 	"github.com/bb/gclaude/internal/config"
+	"github.com/bb/gclaude/internal/dnd"
+	"github.com/bb/gclaude/internal/eventlog"
+	"github.com/bb/gclaude/internal/inbox"
 	"github.com/bb/gclaude/internal/monitor"
+	"github.com/bb/gclaude/internal/notify"
 	"github.com/bb/gclaude/internal/session"
+	"github.com/bb/gclaude/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
@@ -43,9 +49,18 @@ func init() {
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(attachCmd)
+	rootCmd.AddCommand(resumeCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(cleanupCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(dndCmd)
+	rootCmd.AddCommand(muteCmd)
+	rootCmd.AddCommand(unmuteCmd)
+	rootCmd.AddCommand(priorityCmd)
+	rootCmd.AddCommand(inboxCmd)
+	rootCmd.AddCommand(ackCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(worktreeCmd)
 	rootCmd.AddCommand(monitorCmd)
 }
 
@@ -167,11 +182,32 @@ var attachCmd = &cobra.Command{
 	Short:   "Attach to a running session",
 	Args:    cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		// Attaching is answering the session
+		inbox.Ack(args[0])
+
 		mgr := session.NewManager()
 		return mgr.Attach(args[0])
 	},
 }
 
+var resumeCmd = &cobra.Command{
+	Use:   "resume <branch>",
+	Short: "Restart Claude in a session whose agent exited",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr := session.NewManager()
+		if err := mgr.Resume(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Resumed session '%s'\n", args[0])
+
+		if err := spawnMonitor(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to start monitor: %v\n", err)
+		}
+		return nil
+	},
+}
+
 var listCmd = &cobra.Command{
 	Use:     "list",
 	Aliases: []string{"ls"},
@@ -180,6 +216,21 @@ var listCmd = &cobra.Command{
 		mgr := session.NewManager()
 		sessions := mgr.List()
 
+		var unread map[string]int
+		if items, err := inbox.Load(); err == nil {
+			unread = inbox.Unread(items)
+		}
+
+		if listWaiting {
+			var waiting []*session.Session
+			for _, sess := range sessions {
+				if sess.NeedsInput {
+					waiting = append(waiting, sess)
+				}
+			}
+			sessions = waiting
+		}
+
 		if len(sessions) == 0 {
 			fmt.Println("No active sessions")
 			return nil
@@ -194,6 +245,23 @@ var listCmd = &cobra.Command{
 			if sess.NeedsInput {
 				status = "⚠ " + status
 			}
+			if sess.Status == session.StatusExited {
+				status = fmt.Sprintf("%s (%d)", status, sess.ExitCode)
+			}
+			if sess.Status == session.StatusRateLimited {
+				status += " until " + sess.RateLimitedUntil.Local().Format("15:04")
+			}
+			if sess.IsMuted(time.Now()) {
+				status += " 🔇"
+			}
+			if sess.Priority == session.PriorityHigh {
+				status += " ↑"
+			} else if sess.Priority == session.PriorityLow {
+				status += " ↓"
+			}
+			if n := unread[sess.ID]; n > 0 {
+				status += fmt.Sprintf(" ✉%d", n)
+			}
 
 			lastActivity := sess.LastActivity.Format(time.RFC3339)
 			if time.Since(sess.LastActivity) < time.Hour {
@@ -213,6 +281,12 @@ var listCmd = &cobra.Command{
 	},
 }
 
+var listWaiting bool
+
+func init() {
+	listCmd.Flags().BoolVar(&listWaiting, "waiting", false, "Only show sessions waiting for input")
+}
+
 func truncatePath(path string, maxLen int) string {
 	if len(path) <= maxLen {
 		return path
@@ -239,6 +313,320 @@ var cleanupCmd = &cobra.Command{
 	},
 }
 
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment and reconcile sessions with tmux",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, bin := range []string{"tmux", "git", "claude"} {
+			if path, err := exec.LookPath(bin); err != nil {
+				fmt.Printf("✗ %s not found in PATH\n", bin)
+			} else {
+				fmt.Printf("✓ %s: %s\n", bin, path)
+			}
+		}
+
+		mgr := session.NewManager()
+		report, err := session.Reconcile(mgr.GetStore())
+		if err != nil {
+			return fmt.Errorf("failed to reconcile sessions: %w", err)
+		}
+
+		if report.Empty() {
+			fmt.Println("✓ sessions match tmux")
+		}
+		for _, sess := range report.Stale {
+			fmt.Printf("✗ %s: tmux session %s is gone (remove with 'gclaude cleanup')\n", sess.Branch, sess.TmuxSession)
+		}
+		for _, name := range report.Orphans {
+			fmt.Printf("✗ tmux session %s has no gclaude session (attach with 'tmux attach -t %s')\n", name, name)
+		}
+		for _, sess := range report.MissingWorktrees {
+			fmt.Printf("✗ %s: worktree %s no longer exists\n", sess.Branch, sess.WorktreePath)
+		}
+
+		events, err := eventlog.Read(10)
+		if err != nil {
+			return err
+		}
+		if len(events) > 0 {
+			fmt.Println("\nRecent events:")
+			for _, ev := range events {
+				branch := ev.Branch
+				if branch == "" {
+					branch = "-"
+				}
+				fmt.Printf("  %s  %-14s %-20s %s\n", ev.Time.Local().Format("01-02 15:04:05"), ev.Kind, branch, ev.Message)
+			}
+		}
+		return nil
+	},
+}
+
+var dndCmd = &cobra.Command{
+	Use:   "dnd [on|off|for <duration>]",
+	Short: "Show or change do-not-disturb",
+	Long: `Hold back notifications until do-not-disturb is switched off.
+
+Held notifications are delivered as one summary when it ends. Quiet hours
+(notification.quiet_hours) work the same way on a schedule.`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := dnd.Load()
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			now := time.Now()
+			switch {
+			case st.On:
+				fmt.Println("Do-not-disturb: on")
+			case st.Enabled(now):
+				fmt.Printf("Do-not-disturb: on until %s\n", st.Until.Local().Format("15:04"))
+			default:
+				fmt.Println("Do-not-disturb: off")
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			if len(cfg.Notification.QuietHours.Windows) > 0 {
+				fmt.Printf("Quiet hours: %s", dnd.FormatWindows(cfg.Notification.QuietHours.Windows))
+				if dnd.InQuietHours(cfg.Notification.QuietHours.Windows, now) {
+					fmt.Print(" (active)")
+				}
+				fmt.Println()
+			}
+			return nil
+		}
+
+		switch args[0] {
+		case "on":
+			st = &dnd.State{On: true}
+		case "off":
+			st = &dnd.State{}
+		case "for":
+			if len(args) != 2 {
+				return fmt.Errorf("usage: gclaude dnd for <duration>")
+			}
+			d, err := time.ParseDuration(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid duration: %w", err)
+			}
+			st = &dnd.State{Until: time.Now().Add(d)}
+		default:
+			return fmt.Errorf("unknown dnd mode: %s", args[0])
+		}
+
+		if err := dnd.Save(st); err != nil {
+			return err
+		}
+
+		switch {
+		case st.On:
+			fmt.Println("Do-not-disturb on")
+		case st.Until.IsZero():
+			fmt.Println("Do-not-disturb off")
+		default:
+			fmt.Printf("Do-not-disturb on until %s\n", st.Until.Format("15:04"))
+		}
+		return nil
+	},
+}
+
+var muteFor time.Duration
+
+var muteCmd = &cobra.Command{
+	Use:   "mute <branch>",
+	Short: "Silence notifications for a session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var until time.Time
+		if muteFor > 0 {
+			until = time.Now().Add(muteFor)
+		}
+
+		mgr := session.NewManager()
+		if err := mgr.Mute(args[0], until); err != nil {
+			return err
+		}
+
+		if until.IsZero() {
+			fmt.Printf("Muted '%s'\n", args[0])
+		} else {
+			fmt.Printf("Muted '%s' until %s\n", args[0], until.Format("15:04"))
+		}
+		return nil
+	},
+}
+
+var unmuteCmd = &cobra.Command{
+	Use:   "unmute <branch>",
+	Short: "Re-enable notifications for a session",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		mgr := session.NewManager()
+		if err := mgr.Unmute(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Unmuted '%s'\n", args[0])
+		return nil
+	},
+}
+
+var priorityCmd = &cobra.Command{
+	Use:   "priority <branch> <high|normal|low>",
+	Short: "Set a session's notification priority",
+	Long: `Set a session's notification priority.
+
+High priority sessions notify urgently and skip digests. Low priority
+sessions never play sounds and are always batched into digests. See
+notification.priorities in the config file to change this.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		priority, err := session.ParsePriority(args[1])
+		if err != nil {
+			return err
+		}
+
+		mgr := session.NewManager()
+		if err := mgr.SetPriority(args[0], priority); err != nil {
+			return err
+		}
+		fmt.Printf("Set priority of '%s' to %s\n", args[0], priority)
+		return nil
+	},
+}
+
+var inboxUnread bool
+
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "List recent notifications",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		items, err := inbox.Load()
+		if err != nil {
+			return err
+		}
+
+		var shown []inbox.Item
+		for _, it := range items {
+			if !inboxUnread || !it.Acked {
+				shown = append(shown, it)
+			}
+		}
+		if len(shown) == 0 {
+			fmt.Println("No notifications")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "\tAGE\tBRANCH\tSTATE\tMESSAGE")
+		// Newest first
+		for i := len(shown) - 1; i >= 0; i-- {
+			it := shown[i]
+			marker := "●"
+			if it.Acked {
+				marker = " "
+			}
+
+			age := time.Since(it.Time).Round(time.Second).String() + " ago"
+			if time.Since(it.Time) >= time.Hour {
+				age = it.Time.Local().Format("Jan 2 15:04")
+			}
+
+			message := it.Message
+			if it.Excerpt != "" {
+				message += ": " + it.Excerpt
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", marker, age, it.Branch, it.State, message)
+		}
+		w.Flush()
+		return nil
+	},
+}
+
+var ackCmd = &cobra.Command{
+	Use:   "ack <branch|all>",
+	Short: "Mark notifications as read",
+	Long: `Mark the notifications of a session, or all of them, as read.
+
+Acknowledged sessions no longer escalate.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		n, err := inbox.Ack(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Acknowledged %d notification(s)\n", n)
+		return nil
+	},
+}
+
+func init() {
+	inboxCmd.Flags().BoolVar(&inboxUnread, "unread", false, "Only show unread notifications")
+	muteCmd.Flags().DurationVar(&muteFor, "for", 0, "Mute for a duration (e.g. 2h) instead of until unmuted")
+}
+
+var worktreeCmd = &cobra.Command{
+	Use:   "worktree",
+	Short: "Manage session worktrees",
+}
+
+var relocateDryRun bool
+
+var worktreeRelocateCmd = &cobra.Command{
+	Use:   "relocate [branch]",
+	Short: "Move worktrees to the configured location",
+	Long: `Move the worktrees of the current repository (or only the given
+branch's) to where worktree.strategy puts them, using 'git worktree move'.
+
+Worktrees of running sessions are skipped; stop them first.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+		var branch string
+		if len(args) > 0 {
+			branch = args[0]
+		}
+
+		mgr := session.NewManager()
+		moves, err := mgr.RelocateWorktrees(cwd, branch, relocateDryRun)
+		if err != nil {
+			return err
+		}
+		if len(moves) == 0 {
+			fmt.Println("All worktrees are already in place")
+			return nil
+		}
+
+		failed := 0
+		for _, mv := range moves {
+			switch {
+			case mv.Err != nil:
+				fmt.Printf("✗ %s: %v\n", mv.Branch, mv.Err)
+				failed++
+			case relocateDryRun:
+				fmt.Printf("  %s: %s -> %s\n", mv.Branch, mv.From, mv.To)
+			default:
+				fmt.Printf("✓ %s: %s -> %s\n", mv.Branch, mv.From, mv.To)
+			}
+		}
+		if failed > 0 {
+			return fmt.Errorf("%d worktree(s) not moved", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	worktreeRelocateCmd.Flags().BoolVar(&relocateDryRun, "dry-run", false, "Only show what would be moved")
+	worktreeCmd.AddCommand(worktreeRelocateCmd)
+}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage configuration",
@@ -256,9 +644,61 @@ var configShowCmd = &cobra.Command{
 		fmt.Printf("notification.desktop: %v\n", cfg.Notification.Desktop)
 		fmt.Printf("notification.sound: %v\n", cfg.Notification.Sound)
 		fmt.Printf("notification.sound_file: %s\n", cfg.Notification.SoundFile)
+		for _, event := range []string{"permission", "finished", "error", "stuck"} {
+			if file, ok := cfg.Notification.SoundFiles[event]; ok {
+				fmt.Printf("notification.sound_file.%s: %s\n", event, file)
+			}
+		}
+		fmt.Printf("notification.sound_volume: %d\n", cfg.Notification.SoundVolume)
+		fmt.Printf("notification.sound_player: %s\n", cfg.Notification.SoundPlayer)
+		fmt.Printf("notification.terminal: %s\n", cfg.Notification.Terminal)
+		fmt.Printf("notification.excerpt_chars: %d\n", cfg.Notification.ExcerptChars)
+		fmt.Printf("notification.inbox_size: %d\n", cfg.Notification.InboxSize)
+		fmt.Printf("notification.focus_detector: %s (using %s)\n", cfg.Notification.FocusDetector,
+			notify.NewFocusDetector(cfg.Notification.FocusDetector).Name())
+		fmt.Printf("notification.quiet_hours: %s\n", dnd.FormatWindows(cfg.Notification.QuietHours.Windows))
+		fmt.Printf("notification.quiet_hours.allow: %s\n", strings.Join(cfg.Notification.QuietHours.Allow, ","))
+		fmt.Printf("notification.digest.window_s: %d\n", cfg.Notification.Digest.WindowS)
+		fmt.Printf("notification.digest.immediate: %s\n", strings.Join(cfg.Notification.Digest.Immediate, ","))
+		for _, step := range cfg.Notification.Escalation {
+			fmt.Printf("notification.escalation: after %dm", step.AfterMin)
+			if step.Urgency != "" {
+				fmt.Printf(" urgency=%s", step.Urgency)
+			}
+			if len(step.Notifiers) > 0 {
+				fmt.Printf(" notifiers=%s", strings.Join(step.Notifiers, ","))
+			}
+			fmt.Println()
+		}
+		for _, nc := range notify.EnabledNotifiers(cfg.Notification) {
+			fmt.Printf("notification.notifier: %s", nc.Type)
+			if len(nc.Events) > 0 {
+				fmt.Printf(" events=%s", strings.Join(nc.Events, ","))
+			}
+			if len(nc.Options) > 0 {
+				fmt.Printf(" options=%s", nc.Options)
+			}
+			fmt.Println()
+		}
 		fmt.Printf("monitor.poll_interval_ms: %d\n", cfg.Monitor.PollIntervalMs)
 		fmt.Printf("monitor.idle_threshold_s: %d\n", cfg.Monitor.IdleThresholdS)
 		fmt.Printf("monitor.debounce_secs: %d\n", cfg.Monitor.DebounceSecs)
+		fmt.Printf("monitor.max_notifications_per_min: %d\n", cfg.Monitor.MaxNotificationsPerMin)
+		fmt.Printf("monitor.idle_poll_interval_ms: %d\n", cfg.Monitor.IdlePollIntervalMs)
+		fmt.Printf("monitor.workers: %d\n", cfg.Monitor.Workers)
+		fmt.Printf("monitor.reconcile_interval_s: %d\n", cfg.Monitor.ReconcileIntervalS)
+		fmt.Printf("monitor.stall_threshold_s: %d\n", cfg.Monitor.StallThresholdS)
+		fmt.Printf("monitor.interrupt_on_stall: %v\n", cfg.Monitor.InterruptOnStall)
+		fmt.Printf("monitor.auto_continue: %v\n", cfg.Monitor.AutoContinue)
+		fmt.Printf("monitor.continue_message: %s\n", cfg.Monitor.ContinueMessage)
+		fmt.Printf("monitor.max_continue_retries: %d\n", cfg.Monitor.MaxContinueRetries)
+		fmt.Printf("monitor.api_error_retry_s: %d\n", cfg.Monitor.APIErrorRetryS)
+		fmt.Printf("monitor.normalize.strip_ansi: %v\n", cfg.Monitor.Normalize.StripANSI)
+		fmt.Printf("monitor.normalize.mask_digits: %v\n", cfg.Monitor.Normalize.MaskDigits)
+		fmt.Printf("monitor.normalize.ignore_tail_lines: %d\n", cfg.Monitor.Normalize.IgnoreTailLines)
+		fmt.Printf("worktree.strategy: %s\n", cfg.Worktree.Strategy)
+		fmt.Printf("worktree.root: %s\n", cfg.Worktree.Root)
+		fmt.Printf("worktree.template: %s\n", cfg.Worktree.Template)
 		return nil
 	},
 }
@@ -281,6 +721,107 @@ var configSetCmd = &cobra.Command{
 			cfg.Notification.Sound = value == "true"
 		case "notification.sound_file":
 			cfg.Notification.SoundFile = value
+		case "notification.sound_file.permission", "notification.sound_file.finished",
+			"notification.sound_file.error", "notification.sound_file.stuck":
+			event := strings.TrimPrefix(key, "notification.sound_file.")
+			if cfg.Notification.SoundFiles == nil {
+				cfg.Notification.SoundFiles = make(map[string]string)
+			}
+			if value == "" {
+				delete(cfg.Notification.SoundFiles, event)
+			} else {
+				cfg.Notification.SoundFiles[event] = value
+			}
+		case "notification.sound_volume":
+			if err := setInt(&cfg.Notification.SoundVolume, key, value); err != nil {
+				return err
+			}
+			if cfg.Notification.SoundVolume < 0 || cfg.Notification.SoundVolume > 100 {
+				return fmt.Errorf("%s must be between 0 and 100", key)
+			}
+		case "notification.sound_player":
+			cfg.Notification.SoundPlayer = value
+		case "notification.terminal":
+			cfg.Notification.Terminal = value
+		case "notification.focus_detector":
+			cfg.Notification.FocusDetector = value
+		case "notification.excerpt_chars":
+			if err := setInt(&cfg.Notification.ExcerptChars, key, value); err != nil {
+				return err
+			}
+		case "notification.inbox_size":
+			if err := setInt(&cfg.Notification.InboxSize, key, value); err != nil {
+				return err
+			}
+		case "notification.quiet_hours":
+			windows, err := dnd.ParseWindows(value)
+			if err != nil {
+				return err
+			}
+			cfg.Notification.QuietHours.Windows = windows
+		case "notification.digest.window_s":
+			if err := setInt(&cfg.Notification.Digest.WindowS, key, value); err != nil {
+				return err
+			}
+		case "notification.digest.immediate":
+			cfg.Notification.Digest.Immediate = splitList(value)
+		case "notification.quiet_hours.allow":
+			cfg.Notification.QuietHours.Allow = splitList(value)
+		case "monitor.debounce_secs":
+			if err := setInt(&cfg.Monitor.DebounceSecs, key, value); err != nil {
+				return err
+			}
+		case "monitor.max_notifications_per_min":
+			if err := setInt(&cfg.Monitor.MaxNotificationsPerMin, key, value); err != nil {
+				return err
+			}
+		case "monitor.idle_poll_interval_ms":
+			if err := setInt(&cfg.Monitor.IdlePollIntervalMs, key, value); err != nil {
+				return err
+			}
+		case "monitor.workers":
+			if err := setInt(&cfg.Monitor.Workers, key, value); err != nil {
+				return err
+			}
+		case "monitor.reconcile_interval_s":
+			if err := setInt(&cfg.Monitor.ReconcileIntervalS, key, value); err != nil {
+				return err
+			}
+		case "monitor.stall_threshold_s":
+			if err := setInt(&cfg.Monitor.StallThresholdS, key, value); err != nil {
+				return err
+			}
+		case "monitor.interrupt_on_stall":
+			cfg.Monitor.InterruptOnStall = value == "true"
+		case "monitor.auto_continue":
+			cfg.Monitor.AutoContinue = value == "true"
+		case "monitor.continue_message":
+			cfg.Monitor.ContinueMessage = value
+		case "monitor.max_continue_retries":
+			if err := setInt(&cfg.Monitor.MaxContinueRetries, key, value); err != nil {
+				return err
+			}
+		case "monitor.api_error_retry_s":
+			if err := setInt(&cfg.Monitor.APIErrorRetryS, key, value); err != nil {
+				return err
+			}
+		case "monitor.normalize.strip_ansi":
+			cfg.Monitor.Normalize.StripANSI = value == "true"
+		case "monitor.normalize.mask_digits":
+			cfg.Monitor.Normalize.MaskDigits = value == "true"
+		case "monitor.normalize.ignore_tail_lines":
+			if err := setInt(&cfg.Monitor.Normalize.IgnoreTailLines, key, value); err != nil {
+				return err
+			}
+		case "worktree.strategy":
+			if err := worktree.ValidateStrategy(value); err != nil {
+				return err
+			}
+			cfg.Worktree.Strategy = value
+		case "worktree.root":
+			cfg.Worktree.Root = value
+		case "worktree.template":
+			cfg.Worktree.Template = value
 		default:
 			return fmt.Errorf("unknown config key: %s", key)
 		}
@@ -294,6 +835,26 @@ var configSetCmd = &cobra.Command{
 	},
 }
 
+// splitList parses a comma separated config value
+func splitList(value string) []string {
+	list := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func setInt(field *int, key, value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	*field = n
+	return nil
+}
+
 func init() {
 	configCmd.AddCommand(configShowCmd)
 	configCmd.AddCommand(configSetCmd)
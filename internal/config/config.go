@@ -10,18 +10,137 @@ import (
 type Config struct {
 	Notification NotificationConfig `json:"notification"`
 	Monitor      MonitorConfig      `json:"monitor"`
+	Worktree     WorktreeConfig     `json:"worktree"`
+}
+
+// WorktreeConfig decides where worktrees are created. Strategy is one of
+// sibling (<repo>-worktrees next to the repo), in-repo (.gclaude/worktrees
+// inside it), central (Root/<repo>/<branch>) or custom (Template).
+type WorktreeConfig struct {
+	Strategy string `json:"strategy"`
+	Root     string `json:"root,omitempty"`
+	// Template is a text/template over .Repo, .RepoRoot, .Branch and .Home,
+	// e.g. "{{.Home}}/wt/{{.Repo}}-{{.Branch}}"
+	Template string `json:"template,omitempty"`
 }
 
 type NotificationConfig struct {
 	Desktop   bool   `json:"desktop"`
 	Sound     bool   `json:"sound"`
 	SoundFile string `json:"sound_file,omitempty"`
+	// SoundFiles overrides SoundFile per event: permission, finished, error
+	// or stuck
+	SoundFiles map[string]string `json:"sound_files,omitempty"`
+	// SoundVolume is a percentage applied to every sound
+	SoundVolume int `json:"sound_volume"`
+	// SoundPlayer plays the built-in sounds, written as WAV, e.g. "aplay -q".
+	// When empty a known player or ALSA directly is used.
+	SoundPlayer string `json:"sound_player,omitempty"`
+	// Notifiers lists the enabled backends. When empty, Desktop and Sound
+	// decide which of the built-in backends are used.
+	Notifiers []NotifierConfig `json:"notifiers,omitempty"`
+	// Terminal opens sessions from notifications, e.g. "kitty",
+	// "wezterm start", "alacritty -e" or "gnome-terminal --"
+	Terminal string `json:"terminal,omitempty"`
+	// FocusDetector decides whether the user is looking at a session:
+	// auto, x11, sway, i3, hyprland, gnome or tmux
+	FocusDetector string `json:"focus_detector,omitempty"`
+	// InboxSize is how many notifications 'gclaude inbox' keeps, 0 disables it
+	InboxSize int `json:"inbox_size"`
+	// ExcerptChars caps the pane excerpt included in notifications
+	ExcerptChars int              `json:"excerpt_chars"`
+	QuietHours   QuietHoursConfig `json:"quiet_hours"`
+	// Escalation re-notifies about sessions that stay unanswered
+	Escalation []EscalationStep `json:"escalation,omitempty"`
+	Digest     DigestConfig     `json:"digest"`
+	// Priorities overrides delivery for sessions by their priority
+	Priorities map[string]PriorityConfig `json:"priorities,omitempty"`
+}
+
+// PriorityConfig changes how notifications of sessions with a priority are
+// delivered. Empty fields keep the global behaviour.
+type PriorityConfig struct {
+	Urgency string `json:"urgency,omitempty"`
+	// Notifiers limits delivery to these notifier types
+	Notifiers []string `json:"notifiers,omitempty"`
+	// Digest forces (true) or skips (false) digest batching
+	Digest *bool `json:"digest,omitempty"`
+}
+
+// DigestConfig merges notifications arriving within WindowS seconds into one.
+// States listed in Immediate are never batched.
+type DigestConfig struct {
+	WindowS   int      `json:"window_s"`
+	Immediate []string `json:"immediate"`
+}
+
+// EscalationStep re-sends the last notification AfterMin minutes after it was
+// first sent, with the given urgency, to the listed notifier types (all when
+// empty).
+type EscalationStep struct {
+	AfterMin  int      `json:"after_min"`
+	Urgency   string   `json:"urgency,omitempty"`
+	Notifiers []string `json:"notifiers,omitempty"`
+}
+
+// QuietHoursConfig holds notifications back during the listed windows (and
+// while 'gclaude dnd' is on). Only the notifier types in Allow still fire,
+// with low urgency; everything is summarised when the quiet period ends.
+type QuietHoursConfig struct {
+	Windows []QuietWindow `json:"windows,omitempty"`
+	Allow   []string      `json:"allow"`
+}
+
+// QuietWindow is a daily time range, "22:00"-"07:00" runs overnight.
+// Days are mon..sun; empty means every day.
+type QuietWindow struct {
+	Days  []string `json:"days,omitempty"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// NotifierConfig enables one notification backend
+type NotifierConfig struct {
+	Type string `json:"type"`
+	// Events limits the backend to these states (all when empty)
+	Events  []string        `json:"events,omitempty"`
+	Options json.RawMessage `json:"options,omitempty"`
 }
 
 type MonitorConfig struct {
 	PollIntervalMs int `json:"poll_interval_ms"`
 	IdleThresholdS int `json:"idle_threshold_s"`
-	DebounceSecs   int `json:"debounce_secs"`
+	// DebounceSecs suppresses repeats of the same state for a session
+	DebounceSecs int `json:"debounce_secs"`
+	// MaxNotificationsPerMin caps notifications across all sessions
+	MaxNotificationsPerMin int `json:"max_notifications_per_min"`
+	// Sessions waiting for input or attached are polled at this slower rate
+	IdlePollIntervalMs int `json:"idle_poll_interval_ms"`
+	// How often the store is reconciled against tmux and the filesystem
+	ReconcileIntervalS int `json:"reconcile_interval_s"`
+	// Workers bounds how many sessions are captured concurrently
+	Workers       int             `json:"workers"`
+	Normalize     NormalizeConfig `json:"normalize"`
+	AgentCommands []string        `json:"agent_commands"`
+	// StallThresholdS is how long a session may show a working indicator
+	// without real output (or CPU use) before it's reported as stuck.
+	StallThresholdS  int  `json:"stall_threshold_s"`
+	InterruptOnStall bool `json:"interrupt_on_stall"`
+	// AutoContinue sends ContinueMessage once a usage limit resets or after
+	// an API error, at most MaxContinueRetries times in a row.
+	AutoContinue       bool   `json:"auto_continue"`
+	ContinueMessage    string `json:"continue_message"`
+	MaxContinueRetries int    `json:"max_continue_retries"`
+	APIErrorRetryS     int    `json:"api_error_retry_s"`
+}
+
+// NormalizeConfig controls how captured pane output is cleaned up before
+// change detection, so spinners and timers don't count as activity.
+type NormalizeConfig struct {
+	StripANSI       bool     `json:"strip_ansi"`
+	MaskDigits      bool     `json:"mask_digits"`
+	StatusPatterns  []string `json:"status_patterns,omitempty"`
+	IgnoreTailLines int      `json:"ignore_tail_lines"`
 }
 
 var (
@@ -33,18 +152,54 @@ var (
 func DefaultConfig() *Config {
 	return &Config{
 		Notification: NotificationConfig{
-			Desktop:   true,
-			Sound:     true,
-			SoundFile: "",
+			Desktop:      true,
+			Sound:        true,
+			SoundFile:    "",
+			SoundVolume:  100,
+			ExcerptChars: 200,
+			InboxSize:    100,
+			QuietHours: QuietHoursConfig{
+				Allow: []string{"desktop", "dbus"},
+			},
+			Digest: DigestConfig{
+				WindowS:   0,
+				Immediate: []string{"exited", "stuck", "error"},
+			},
+			Priorities: map[string]PriorityConfig{
+				"high": {Urgency: "critical", Digest: boolPtr(false)},
+				"low":  {Urgency: "low", Notifiers: []string{"desktop", "dbus"}, Digest: boolPtr(true)},
+			},
 		},
 		Monitor: MonitorConfig{
-			PollIntervalMs: 500,
-			IdleThresholdS: 10,  // 10 seconds before considering idle
-			DebounceSecs:   30,
+			PollIntervalMs:         500,
+			IdleThresholdS:         10, // 10 seconds before considering idle
+			DebounceSecs:           30,
+			MaxNotificationsPerMin: 10,
+			IdlePollIntervalMs:     2000,
+			Workers:                4,
+			ReconcileIntervalS:     60,
+			Normalize: NormalizeConfig{
+				StripANSI:       true,
+				MaskDigits:      true,
+				IgnoreTailLines: 0,
+			},
+			AgentCommands:      []string{"claude", "node"},
+			StallThresholdS:    900,
+			ContinueMessage:    "continue",
+			MaxContinueRetries: 3,
+			APIErrorRetryS:     300,
+		},
+		Worktree: WorktreeConfig{
+			Strategy: "sibling",
+			Root:     "~/worktrees",
 		},
 	}
 }
 
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 func GetConfigDir() string {
 	cfgOnce.Do(func() {
 		if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
@@ -0,0 +1,184 @@
+package dnd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bb/gclaude/internal/config"
+)
+
+// State is the manual do-not-disturb switch set with 'gclaude dnd'
+type State struct {
+	On    bool      `json:"on"`
+	Until time.Time `json:"until,omitempty"`
+}
+
+func statePath() string {
+	return filepath.Join(config.GetDataDir(), "dnd.json")
+}
+
+func Load() (*State, error) {
+	data, err := os.ReadFile(statePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, err
+	}
+
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+func Save(st *State) error {
+	if err := config.EnsureDataDir(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(), data, 0644)
+}
+
+// Enabled reports whether manual DND is on at now
+func (s *State) Enabled(now time.Time) bool {
+	return s.On || now.Before(s.Until)
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// InQuietHours reports whether now falls in one of the configured windows.
+// A window whose end is before its start runs overnight, and belongs to the
+// day it starts on.
+func InQuietHours(windows []config.QuietWindow, now time.Time) bool {
+	for _, w := range windows {
+		start, err1 := parseClock(w.Start)
+		end, err2 := parseClock(w.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		minute := now.Hour()*60 + now.Minute()
+		day := now.Weekday()
+
+		if start <= end {
+			if minute >= start && minute < end && onDay(w.Days, day) {
+				return true
+			}
+			continue
+		}
+
+		// Overnight: the evening part is on the listed day, the morning
+		// part on the day after
+		if minute >= start && onDay(w.Days, day) {
+			return true
+		}
+		if minute < end && onDay(w.Days, (day+6)%7) {
+			return true
+		}
+	}
+	return false
+}
+
+func onDay(days []string, day time.Weekday) bool {
+	if len(days) == 0 {
+		return true
+	}
+	for _, d := range days {
+		if wd, ok := weekdays[strings.ToLower(d)[:min(3, len(d))]]; ok && wd == day {
+			return true
+		}
+	}
+	return false
+}
+
+// parseClock parses "HH:MM" into minutes since midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ValidateWindows checks the times and days of quiet hour windows
+func ValidateWindows(windows []config.QuietWindow) error {
+	for _, w := range windows {
+		if _, err := parseClock(w.Start); err != nil {
+			return err
+		}
+		if _, err := parseClock(w.End); err != nil {
+			return err
+		}
+		for _, d := range w.Days {
+			if _, ok := weekdays[strings.ToLower(d)[:min(3, len(d))]]; !ok {
+				return fmt.Errorf("invalid day %q", d)
+			}
+		}
+	}
+	return nil
+}
+
+// Active reports whether notifications should be held back at now, either
+// because DND is switched on or because of quiet hours.
+func Active(cfg config.QuietHoursConfig, now time.Time) bool {
+	if st, err := Load(); err == nil && st.Enabled(now) {
+		return true
+	}
+	return InQuietHours(cfg.Windows, now)
+}
+
+// ParseWindows parses windows written as "[days] HH:MM-HH:MM", separated by
+// ";", e.g. "mon,tue,wed,thu,fri 22:00-07:00; sat,sun 00:00-10:00".
+func ParseWindows(s string) ([]config.QuietWindow, error) {
+	var windows []config.QuietWindow
+	for _, part := range strings.Split(s, ";") {
+		fields := strings.Fields(part)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var w config.QuietWindow
+		span := fields[len(fields)-1]
+		if len(fields) == 2 {
+			w.Days = strings.Split(fields[0], ",")
+		} else if len(fields) > 2 {
+			return nil, fmt.Errorf("invalid quiet hours %q", part)
+		}
+
+		start, end, ok := strings.Cut(span, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid time range %q, want HH:MM-HH:MM", span)
+		}
+		w.Start, w.End = start, end
+		windows = append(windows, w)
+	}
+
+	if err := ValidateWindows(windows); err != nil {
+		return nil, err
+	}
+	return windows, nil
+}
+
+// FormatWindows is the inverse of ParseWindows
+func FormatWindows(windows []config.QuietWindow) string {
+	parts := make([]string, len(windows))
+	for i, w := range windows {
+		parts[i] = w.Start + "-" + w.End
+		if len(w.Days) > 0 {
+			parts[i] = strings.Join(w.Days, ",") + " " + parts[i]
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,123 @@
+package eventlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/bb/gclaude/internal/config"
+	"github.com/bb/gclaude/internal/filelock"
+)
+
+// Event is a single entry in the event log
+type Event struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"`
+	Branch  string    `json:"branch,omitempty"`
+	Message string    `json:"message"`
+}
+
+// When the log grows past maxSize it's cut back to the last keepEvents
+// entries, and to at most half of maxSize so it isn't cut again right away
+const (
+	maxSize    = 1 << 20
+	keepEvents = 2000
+)
+
+func logPath() string {
+	return filepath.Join(config.GetDataDir(), "events.jsonl")
+}
+
+// Log appends an event to the event log in the data dir. Errors are ignored,
+// the log is best effort and must never break the monitor.
+func Log(kind, branch, format string, args ...any) {
+	ev := Event{
+		Time:    time.Now(),
+		Kind:    kind,
+		Branch:  branch,
+		Message: fmt.Sprintf(format, args...),
+	}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+
+	if err := config.EnsureDataDir(); err != nil {
+		return
+	}
+	unlock, err := filelock.Lock(logPath())
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(logPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	f.Write(append(data, '\n'))
+	info, err := f.Stat()
+	f.Close()
+	if err == nil && info.Size() > maxSize {
+		truncate()
+	}
+}
+
+// truncate drops the oldest entries. Callers hold the file lock.
+func truncate() error {
+	lines, err := readLines()
+	if err != nil {
+		return err
+	}
+	keep, size := 0, 0
+	for i := len(lines) - 1; i >= 0 && keep < keepEvents; i-- {
+		size += len(lines[i]) + 1
+		if size > maxSize/2 {
+			break
+		}
+		keep++
+	}
+	lines = lines[len(lines)-keep:]
+
+	tmp := logPath() + ".tmp"
+	if err := os.WriteFile(tmp, append(bytes.Join(lines, []byte("\n")), '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, logPath())
+}
+
+func readLines() ([][]byte, error) {
+	data, err := os.ReadFile(logPath())
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n")), nil
+}
+
+// Read returns the last n events, oldest first. n <= 0 returns everything.
+func Read(n int) ([]Event, error) {
+	lines, err := readLines()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Only parse what's returned; unparseable lines don't count
+	var events []Event
+	for i := len(lines) - 1; i >= 0 && (n <= 0 || len(events) < n); i-- {
+		var ev Event
+		if err := json.Unmarshal(lines[i], &ev); err != nil {
+			continue
+		}
+		events = append(events, ev)
+	}
+	slices.Reverse(events)
+	return events, nil
+}
@@ -0,0 +1,65 @@
+package eventlog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLogIsCapped(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	message := strings.Repeat("x", 1000)
+	total := maxSize/len(message) + 100
+	for i := 0; i < total; i++ {
+		Log("test", "feat", "%d %s", i, message)
+	}
+
+	info, err := os.Stat(logPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > maxSize {
+		t.Errorf("log is %d bytes, want at most %d", info.Size(), maxSize)
+	}
+
+	events, err := Read(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) == 0 || len(events) > keepEvents {
+		t.Fatalf("kept %d events", len(events))
+	}
+	if last := events[len(events)-1].Message; !strings.HasPrefix(last, fmt.Sprintf("%d ", total-1)) {
+		t.Errorf("last event = %.20q, want the newest", last)
+	}
+}
+
+func TestReadLast(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	if events, err := Read(10); err != nil || events != nil {
+		t.Fatalf("Read() of a missing log = %v, %v", events, err)
+	}
+
+	for i := 0; i < 5; i++ {
+		Log("test", "", "%d", i)
+	}
+	f, _ := os.OpenFile(logPath(), os.O_APPEND|os.O_WRONLY, 0644)
+	f.WriteString("not json\n")
+	f.Close()
+	Log("test", "", "5")
+
+	events, err := Read(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, ev := range events {
+		got = append(got, ev.Message)
+	}
+	if strings.Join(got, ",") != "3,4,5" {
+		t.Errorf("Read(3) = %v, want 3,4,5", got)
+	}
+}
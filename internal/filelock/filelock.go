@@ -0,0 +1,25 @@
+// Package filelock serializes read-modify-write cycles on files shared by
+// the monitor and CLI commands.
+package filelock
+
+import (
+	"os"
+	"syscall"
+)
+
+// Lock takes an exclusive flock on path+".lock", waiting for other holders,
+// and returns the function that releases it.
+func Lock(path string) (func(), error) {
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
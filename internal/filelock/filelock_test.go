@@ -0,0 +1,39 @@
+package filelock
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockExcludes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	unlock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		unlock2, err := Lock(path)
+		if err != nil {
+			t.Error(err)
+		}
+		acquired <- unlock2
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("second Lock did not wait for the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	select {
+	case unlock2 := <-acquired:
+		unlock2()
+	case <-time.After(time.Second):
+		t.Fatal("second Lock not acquired after unlock")
+	}
+}
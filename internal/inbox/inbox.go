@@ -0,0 +1,120 @@
+package inbox
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bb/gclaude/internal/config"
+	"github.com/bb/gclaude/internal/filelock"
+)
+
+// Item is a notification kept until the user acknowledges it with 'gclaude ack'
+type Item struct {
+	Time      time.Time `json:"time"`
+	SessionID string    `json:"session_id"`
+	Branch    string    `json:"branch"`
+	State     string    `json:"state"`
+	Message   string    `json:"message"`
+	Excerpt   string    `json:"excerpt,omitempty"`
+	Acked     bool      `json:"acked,omitempty"`
+}
+
+func inboxPath() string {
+	return filepath.Join(config.GetDataDir(), "inbox.json")
+}
+
+// Load returns the kept notifications, oldest first
+func Load() ([]Item, error) {
+	data, err := os.ReadFile(inboxPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// lock serializes Add and Ack between the monitor and CLI commands
+func lock() (func(), error) {
+	if err := config.EnsureDataDir(); err != nil {
+		return nil, err
+	}
+	return filelock.Lock(inboxPath())
+}
+
+func save(items []Item) error {
+	data, err := json.MarshalIndent(items, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := inboxPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, inboxPath())
+}
+
+// Add appends item, keeping only the last limit notifications
+func Add(item Item, limit int) error {
+	unlock, err := lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	items, err := Load()
+	if err != nil {
+		return err
+	}
+	items = append(items, item)
+	if limit > 0 && len(items) > limit {
+		items = items[len(items)-limit:]
+	}
+	return save(items)
+}
+
+// Ack marks the notifications of branch as read, or all of them for "all".
+// It returns how many were unread.
+func Ack(branch string) (int, error) {
+	unlock, err := lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	items, err := Load()
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for i := range items {
+		if !items[i].Acked && (branch == "all" || items[i].Branch == branch) {
+			items[i].Acked = true
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, save(items)
+}
+
+// Unread counts unacknowledged notifications per session ID
+func Unread(items []Item) map[string]int {
+	unread := make(map[string]int)
+	for _, it := range items {
+		if !it.Acked {
+			unread[it.SessionID]++
+		}
+	}
+	return unread
+}
@@ -0,0 +1,49 @@
+package inbox
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentAddKeepsEveryItem(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	// Each Add opens its own lock file descriptor, as separate processes would
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := Add(Item{SessionID: fmt.Sprint(i), Branch: "feat"}, 0); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	items, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 20 {
+		t.Fatalf("got %d items, want 20", len(items))
+	}
+
+	n, err := Ack("feat")
+	if err != nil || n != 20 {
+		t.Errorf("Ack() = %d, %v, want 20", n, err)
+	}
+	if unread := Unread(mustLoad(t)); len(unread) != 0 {
+		t.Errorf("unread after ack: %v", unread)
+	}
+}
+
+func mustLoad(t *testing.T) []Item {
+	t.Helper()
+	items, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
@@ -0,0 +1,37 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/bb/gclaude/internal/eventlog"
+	"github.com/bb/gclaude/internal/notify"
+	"github.com/bb/gclaude/internal/session"
+)
+
+const snoozeDuration = 10 * time.Minute
+
+// handleAction runs an action the user clicked on a notification
+func (m *Monitor) handleAction(ev notify.Event, action string) {
+	if ev.IsSummary() {
+		return
+	}
+	switch action {
+	case notify.ActionAttach:
+		if err := notify.OpenSession(m.cfg.Notification.Terminal, ev.Branch, ev.TmuxSession); err != nil {
+			eventlog.Log("action", ev.Branch, "attach failed: %v", err)
+			return
+		}
+		eventlog.Log("action", ev.Branch, "opened terminal")
+	case notify.ActionSnooze:
+		until := time.Now().Add(snoozeDuration)
+		m.store.Modify(ev.SessionID, func(s *session.Session) {
+			s.MutedUntil = until
+		})
+		eventlog.Log("action", ev.Branch, "snoozed until %s", until.Format("15:04"))
+	case notify.ActionMute:
+		m.store.Modify(ev.SessionID, func(s *session.Session) {
+			s.Muted = true
+		})
+		eventlog.Log("action", ev.Branch, "muted")
+	}
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bb/gclaude/internal/notify"
+)
+
+// debouncer drops notifications that repeat a session's state within window,
+// and any beyond perMinute across all sessions. A session flipping between
+// running and waiting after every approval would otherwise notify each time.
+type debouncer struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	window    time.Duration
+	perMinute int
+	// Last notification per session and state
+	last map[debounceKey]time.Time
+	// Notifications sent in the last minute, oldest first
+	recent []time.Time
+}
+
+type debounceKey struct {
+	session string
+	state   notify.State
+}
+
+// newDebouncer returns a debouncer reading the time from now. A zero window
+// or perMinute disables that limit.
+func newDebouncer(window time.Duration, perMinute int, now func() time.Time) *debouncer {
+	return &debouncer{
+		now:       now,
+		window:    window,
+		perMinute: perMinute,
+		last:      make(map[debounceKey]time.Time),
+	}
+}
+
+// Allow reports whether a notification about sessionID in state may be sent,
+// and counts it if so
+func (d *debouncer) Allow(sessionID string, state notify.State) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	key := debounceKey{sessionID, state}
+
+	if d.window > 0 {
+		for k, t := range d.last {
+			if now.Sub(t) >= d.window {
+				delete(d.last, k)
+			}
+		}
+		if _, ok := d.last[key]; ok {
+			return false
+		}
+	}
+
+	if d.perMinute > 0 {
+		i := 0
+		for i < len(d.recent) && now.Sub(d.recent[i]) >= time.Minute {
+			i++
+		}
+		d.recent = d.recent[i:]
+		if len(d.recent) >= d.perMinute {
+			return false
+		}
+		d.recent = append(d.recent, now)
+	}
+
+	if d.window > 0 {
+		d.last[key] = now
+	}
+	return true
+}
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bb/gclaude/internal/notify"
+)
+
+// fakeClock is a settable time source for the debouncer
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time          { return c.t }
+func (c *fakeClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestClock() *fakeClock {
+	return &fakeClock{t: time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)}
+}
+
+func TestDebouncerWindow(t *testing.T) {
+	clock := newTestClock()
+	d := newDebouncer(30*time.Second, 0, clock.now)
+
+	if !d.Allow("a", notify.StateWaiting) {
+		t.Fatal("first notification dropped")
+	}
+
+	clock.advance(10 * time.Second)
+	if d.Allow("a", notify.StateWaiting) {
+		t.Error("same state within the window allowed")
+	}
+	if !d.Allow("a", notify.StateFinished) {
+		t.Error("different state dropped")
+	}
+	if !d.Allow("b", notify.StateWaiting) {
+		t.Error("same state for another session dropped")
+	}
+
+	// The window runs from the first notification, not the dropped repeat
+	clock.advance(20 * time.Second)
+	if !d.Allow("a", notify.StateWaiting) {
+		t.Error("same state after the window expired dropped")
+	}
+	clock.advance(29 * time.Second)
+	if d.Allow("a", notify.StateWaiting) {
+		t.Error("window not restarted by the allowed notification")
+	}
+}
+
+func TestDebouncerPerMinute(t *testing.T) {
+	clock := newTestClock()
+	d := newDebouncer(0, 3, clock.now)
+
+	for i, session := range []string{"a", "b", "c"} {
+		if !d.Allow(session, notify.StateWaiting) {
+			t.Fatalf("notification %d dropped under the cap", i)
+		}
+		clock.advance(10 * time.Second)
+	}
+	if d.Allow("d", notify.StateWaiting) {
+		t.Error("fourth notification in a minute allowed")
+	}
+
+	// 60s after the first, it drops out of the window and frees one slot
+	clock.advance(30 * time.Second)
+	if !d.Allow("d", notify.StateWaiting) {
+		t.Error("notification dropped after the oldest one expired")
+	}
+	if d.Allow("e", notify.StateWaiting) {
+		t.Error("more than one slot freed")
+	}
+
+	clock.advance(10 * time.Second)
+	if !d.Allow("e", notify.StateWaiting) {
+		t.Error("notification dropped after the second oldest one expired")
+	}
+}
+
+func TestDebouncerDroppedDoesNotCount(t *testing.T) {
+	clock := newTestClock()
+	d := newDebouncer(time.Minute, 2, clock.now)
+
+	d.Allow("a", notify.StateWaiting)
+	// Debounced repeats don't use up the per-minute cap
+	for i := 0; i < 5; i++ {
+		d.Allow("a", notify.StateWaiting)
+	}
+	if !d.Allow("b", notify.StateWaiting) {
+		t.Error("debounced repeats counted towards the cap")
+	}
+	// Capped notifications don't start a window
+	if d.Allow("c", notify.StateWaiting) {
+		t.Fatal("cap not applied")
+	}
+	clock.advance(time.Minute)
+	if !d.Allow("c", notify.StateWaiting) {
+		t.Error("capped notification started a debounce window")
+	}
+}
@@ -0,0 +1,140 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bb/gclaude/internal/notify"
+)
+
+// Window used when a priority forces batching but no digest window is set
+const defaultDigestWindow = time.Minute
+
+type heldEvent struct {
+	ev    notify.Event
+	allow []string
+}
+
+// batch holds ev for the digest if digests are enabled and ev isn't urgent,
+// or if force says so. It returns false when ev should be sent right away.
+func (m *Monitor) batch(ev notify.Event, allow []string, force *bool) bool {
+	cfg := m.cfg.Notification.Digest
+	if force != nil {
+		if !*force {
+			return false
+		}
+	} else {
+		if cfg.WindowS <= 0 || ev.Urgency == notify.UrgencyCritical {
+			return false
+		}
+		for _, s := range cfg.Immediate {
+			if notify.State(s) == ev.State {
+				return false
+			}
+		}
+	}
+
+	m.digestMu.Lock()
+	defer m.digestMu.Unlock()
+
+	// A newer event for the same session replaces the older one
+	for i, held := range m.digestHeld {
+		if held.ev.SessionID == ev.SessionID {
+			m.digestHeld[i] = heldEvent{ev, allow}
+			return true
+		}
+	}
+	if len(m.digestHeld) == 0 {
+		m.digestStart = ev.Time
+	}
+	m.digestHeld = append(m.digestHeld, heldEvent{ev, allow})
+	return true
+}
+
+// flushDigest sends the held events once the digest window has passed, or
+// right away with force (on shutdown)
+func (m *Monitor) flushDigest(now time.Time, force bool) {
+	window := time.Duration(m.cfg.Notification.Digest.WindowS) * time.Second
+	if window <= 0 {
+		window = defaultDigestWindow
+	}
+
+	m.digestMu.Lock()
+	if len(m.digestHeld) == 0 || (!force && now.Sub(m.digestStart) < window) {
+		m.digestMu.Unlock()
+		return
+	}
+	held := m.digestHeld
+	m.digestHeld = nil
+	m.digestMu.Unlock()
+
+	if len(held) == 1 {
+		m.emit(held[0].ev, held[0].allow)
+		return
+	}
+
+	// The digest may use any notifier one of its events was allowed to
+	events := make([]notify.Event, len(held))
+	allow := []string{}
+	for i, h := range held {
+		events[i] = h.ev
+		if allow != nil && h.allow != nil {
+			allow = append(allow, h.allow...)
+		} else {
+			allow = nil
+		}
+	}
+	m.emit(digestEvent(events, now), allow)
+}
+
+var digestVerbs = map[notify.State]string{
+	notify.StateWaiting:     "need input",
+	notify.StateFinished:    "finished",
+	notify.StateRateLimited: "are rate limited",
+}
+
+// digestEvent merges several events into one, e.g.
+// "3 sessions need input: api-auth, docs, migrate-db"
+func digestEvent(held []notify.Event, now time.Time) notify.Event {
+	sort.Slice(held, func(i, j int) bool { return held[i].Time.Before(held[j].Time) })
+
+	state := held[0].State
+	urgency := notify.UrgencyLow
+	branches := make([]string, len(held))
+	breakdown := make([]string, len(held))
+	for i, ev := range held {
+		if ev.State != state {
+			state = notify.StateSummary
+		}
+		if ev.Urgency == notify.UrgencyNormal {
+			urgency = notify.UrgencyNormal
+		}
+		branches[i] = ev.Branch
+		breakdown[i] = fmt.Sprintf("%s: %s", ev.Branch, ev.Message)
+		if ev.Excerpt != "" {
+			breakdown[i] += " - " + firstLine(ev.Excerpt)
+		}
+	}
+
+	verb, ok := digestVerbs[state]
+	if !ok {
+		verb = "need attention"
+	}
+	title := fmt.Sprintf("%d sessions %s: %s", len(held), verb, strings.Join(branches, ", "))
+
+	return notify.Event{
+		Time:    now,
+		Branch:  strings.Join(branches, ", "),
+		State:   state,
+		Urgency: urgency,
+		Title:   "gclaude: " + title,
+		Message: strings.Join(breakdown, "\n"),
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bb/gclaude/internal/config"
+	"github.com/bb/gclaude/internal/notify"
+	"github.com/bb/gclaude/internal/session"
+)
+
+// recorder is a notifier that keeps what it's sent
+type recorder struct {
+	mu     sync.Mutex
+	events []notify.Event
+}
+
+func (r *recorder) Name() string { return "test-recorder" }
+
+func (r *recorder) Notify(ev notify.Event) error {
+	r.mu.Lock()
+	r.events = append(r.events, ev)
+	r.mu.Unlock()
+	return nil
+}
+
+func newRecordingMonitor(t *testing.T) (*Monitor, *recorder) {
+	t.Helper()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	rec := &recorder{}
+	notify.Register("test-recorder", func(json.RawMessage) (notify.Notifier, error) { return rec, nil })
+
+	cfg := &config.Config{}
+	cfg.Monitor.PollIntervalMs = int(time.Hour / time.Millisecond)
+	cfg.Monitor.ReconcileIntervalS = 3600
+	cfg.Notification.Digest.WindowS = 3600
+	cfg.Notification.Notifiers = []config.NotifierConfig{{Type: "test-recorder"}}
+	return New(session.GetStore(), cfg), rec
+}
+
+func TestStopFlushesHeldEvents(t *testing.T) {
+	m, rec := newRecordingMonitor(t)
+	m.Start()
+
+	now := time.Now()
+	for _, branch := range []string{"api", "docs"} {
+		ev := notify.Event{Time: now, SessionID: branch, Branch: branch, State: notify.StateFinished, Urgency: notify.UrgencyNormal}
+		if !m.batch(ev, nil, nil) {
+			t.Fatal("event not held for the digest")
+		}
+	}
+	m.holdQuiet(notify.Event{Time: now, SessionID: "web", Branch: "web", State: notify.StateWaiting})
+
+	m.Stop()
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if len(rec.events) != 2 {
+		t.Fatalf("got %d notifications on stop, want the digest and the quiet-hours summary", len(rec.events))
+	}
+	if got := rec.events[0].Title; got != "gclaude: 2 sessions finished: api, docs" {
+		t.Errorf("digest title = %q", got)
+	}
+	if rec.events[1].State != notify.StateSummary {
+		t.Errorf("second notification = %+v, want the quiet-hours summary", rec.events[1])
+	}
+}
+
+func TestEscalationBypassesDigest(t *testing.T) {
+	m, _ := newRecordingMonitor(t)
+	defer m.notifier.Close(time.Second)
+
+	ev := notify.Event{Time: time.Now(), SessionID: "api", Branch: "api", State: notify.StateFinished, Urgency: notify.UrgencyNormal}
+
+	// A step without notifiers sends to all of them
+	m.deliver(ev, nil, true)
+	if len(m.digestHeld) != 0 {
+		t.Fatal("escalation was held for the digest")
+	}
+
+	m.deliver(ev, nil, false)
+	if len(m.digestHeld) != 1 {
+		t.Fatal("notification was not held for the digest")
+	}
+}
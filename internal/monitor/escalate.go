@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bb/gclaude/internal/inbox"
+	"github.com/bb/gclaude/internal/notify"
+	"github.com/bb/gclaude/internal/session"
+	"github.com/bb/gclaude/internal/tmux"
+)
+
+// escalate re-sends the notification of a session that is still unanswered,
+// following the configured escalation steps. It stops as soon as the user
+// types into the session or acknowledges it with 'gclaude ack'; output
+// changes reset the state elsewhere.
+func (m *Monitor) escalate(sess *session.Session, state *sessionState, now time.Time) {
+	steps := m.cfg.Notification.Escalation
+	if state.lastEvent == nil || state.escalation >= len(steps) {
+		return
+	}
+
+	waited := now.Sub(state.lastEvent.Time)
+	if idle := tmux.GetClientLastActivity(sess.TmuxSession); idle >= 0 && time.Duration(idle)*time.Second < waited {
+		// The user has been in the session since we notified
+		state.lastEvent = nil
+		return
+	}
+
+	step := steps[state.escalation]
+	if waited < time.Duration(step.AfterMin)*time.Minute {
+		return
+	}
+	if m.acked(sess) {
+		state.lastEvent = nil
+		return
+	}
+	state.escalation++
+
+	ev := *state.lastEvent
+	ev.Time = now
+	ev.Message = fmt.Sprintf("Still unanswered after %s: %s", waited.Round(time.Minute), state.lastEvent.Message)
+	if step.Urgency != "" {
+		ev.Urgency = notify.Urgency(step.Urgency)
+	}
+
+	var allow []string
+	if len(step.Notifiers) > 0 {
+		allow = step.Notifiers
+	}
+	m.deliver(ev, allow, true)
+}
+
+// acked reports whether the user acknowledged all notifications of sess
+func (m *Monitor) acked(sess *session.Session) bool {
+	if m.cfg.Notification.InboxSize <= 0 {
+		return false
+	}
+	items, err := inbox.Load()
+	if err != nil {
+		return false
+	}
+	return inbox.Unread(items)[sess.ID] == 0
+}
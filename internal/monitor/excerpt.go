@@ -0,0 +1,167 @@
+package monitor
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Excerpt is the part of the pane worth showing in a notification
+type Excerpt struct {
+	// Kind is "permission", "question" or "summary"
+	Kind string
+	Text string
+}
+
+var (
+	permissionQuestion = regexp.MustCompile(`Do you want to|Allow (once|all)|Would you like to`)
+	boxBorder          = regexp.MustCompile(`^[╭╰┌└][─━]+`)
+	boxTop             = regexp.MustCompile(`^[╭┌][─━]+`)
+	boxBottom          = regexp.MustCompile(`^[╰└][─━]+`)
+	// Claude Code's own UI lines that never make a good excerpt
+	chromeLine = regexp.MustCompile(`^(>|❯)\s*$|\? for shortcuts|esc to interrupt|^[─━═]+$|auto-accept edits|Bypassing Permissions|^\s*\d+\.\s|context left`)
+)
+
+// boxChars are stripped from the edges of lines inside a box
+const boxChars = "│┃║ \t"
+
+// ExtractExcerpt finds the permission request, the last question Claude asked
+// or its final message in the captured pane, truncated to maxLen runes.
+func ExtractExcerpt(output string, maxLen int) Excerpt {
+	lines := strings.Split(StripANSI(output), "\n")
+	if len(lines) > 60 {
+		lines = lines[len(lines)-60:]
+	}
+
+	ex := permissionExcerpt(lines)
+	if ex.Text == "" {
+		ex = questionExcerpt(lines)
+	}
+	if ex.Text == "" {
+		ex = summaryExcerpt(lines)
+	}
+	ex.Text = truncateRunes(ex.Text, maxLen)
+	return ex
+}
+
+// permissionExcerpt reads the tool and command from a permission prompt box:
+//
+//	╭─────────────────────────╮
+//	│ Bash command            │
+//	│   npm test              │
+//	│ Do you want to proceed? │
+//	╰─────────────────────────╯
+//
+// The same words outside such a box are an ordinary question.
+func permissionExcerpt(lines []string) Excerpt {
+	q := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if permissionQuestion.MatchString(lines[i]) {
+			q = i
+			break
+		}
+	}
+	if q < 0 {
+		return Excerpt{}
+	}
+
+	start, ok := boxStart(lines, q)
+	if !ok {
+		return Excerpt{}
+	}
+
+	var body []string
+	for _, line := range lines[start:q] {
+		if line = strings.Trim(line, boxChars); line != "" {
+			body = append(body, line)
+		}
+	}
+	if len(body) == 0 {
+		// No tool header
+		return Excerpt{}
+	}
+
+	// First line names the tool ("Bash command", "Edit file"), the rest is
+	// the command or file and its description
+	text := body[0]
+	if len(body) > 1 {
+		text += ": " + strings.Join(body[1:], " - ")
+	}
+	return Excerpt{Kind: "permission", Text: text}
+}
+
+// boxStart returns the first line inside the box that contains line i, or
+// false if line i isn't between a top and a bottom border
+func boxStart(lines []string, i int) (int, bool) {
+	start := -1
+	for j := i; j >= 0; j-- {
+		line := strings.TrimSpace(lines[j])
+		if boxBottom.MatchString(line) {
+			return 0, false
+		}
+		if boxTop.MatchString(line) {
+			start = j + 1
+			break
+		}
+	}
+	if start < 0 {
+		return 0, false
+	}
+
+	for _, line := range lines[i:] {
+		line = strings.TrimSpace(line)
+		if boxTop.MatchString(line) {
+			return 0, false
+		}
+		if boxBottom.MatchString(line) {
+			return start, true
+		}
+	}
+	return 0, false
+}
+
+// questionExcerpt returns the last line of Claude's output that asks a question
+func questionExcerpt(lines []string) Excerpt {
+	for i := len(lines) - 1; i >= 0 && i >= len(lines)-30; i-- {
+		line := cleanLine(lines[i])
+		if line == "" || chromeLine.MatchString(line) {
+			continue
+		}
+		if strings.HasSuffix(line, "?") {
+			return Excerpt{Kind: "question", Text: line}
+		}
+	}
+	return Excerpt{}
+}
+
+// summaryExcerpt returns the start of Claude's last message ("⏺ ..."), or the
+// last line that isn't part of the input box
+func summaryExcerpt(lines []string) Excerpt {
+	fallback := ""
+	for i := len(lines) - 1; i >= 0; i-- {
+		raw := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(raw, "⏺") {
+			return Excerpt{Kind: "summary", Text: strings.TrimSpace(strings.TrimPrefix(raw, "⏺"))}
+		}
+		line := cleanLine(raw)
+		if fallback == "" && line != "" && !chromeLine.MatchString(line) && !boxBorder.MatchString(line) {
+			fallback = line
+		}
+	}
+	return Excerpt{Kind: "summary", Text: fallback}
+}
+
+func cleanLine(line string) string {
+	line = strings.Trim(line, boxChars)
+	return strings.TrimSpace(strings.TrimPrefix(line, "⏺"))
+}
+
+func truncateRunes(s string, maxLen int) string {
+	if maxLen <= 0 {
+		return s
+	}
+	r := []rune(s)
+	if len(r) <= maxLen {
+		return s
+	}
+	return string(r[:maxLen-1]) + "…"
+}
@@ -0,0 +1,88 @@
+package monitor
+
+import "testing"
+
+func TestExtractExcerpt(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		kind   string
+		text   string
+	}{
+		{
+			name: "permission prompt",
+			output: `⏺ Let me run the tests.
+
+╭───────────────────────────────────────────╮
+│ Bash command                              │
+│                                           │
+│   npm test                                │
+│   Run the unit tests                      │
+│                                           │
+│ Do you want to proceed?                   │
+│ ❯ 1. Yes                                  │
+│   2. No, and tell Claude what to do       │
+╰───────────────────────────────────────────╯
+`,
+			kind: "permission",
+			text: "Bash command: npm test - Run the unit tests",
+		},
+		{
+			name: "closing question",
+			output: `> fix the login bug
+
+⏺ Read(src/login.ts)
+  ⎿  Read 120 lines
+
+⏺ I fixed the session check in src/login.ts.
+
+  Would you like to also add a regression test?
+` + inputBox,
+			kind: "question",
+			text: "Would you like to also add a regression test?",
+		},
+		{
+			name: "question after an earlier prompt",
+			output: `╭───────────────────────────────────────────╮
+│ Edit file                                 │
+│   src/login.ts                            │
+│ Do you want to make this edit?            │
+╰───────────────────────────────────────────╯
+
+⏺ Done. Do you want to deploy it now?
+` + inputBox,
+			kind: "question",
+			text: "Done. Do you want to deploy it now?",
+		},
+		{
+			name: "prompt without a tool header",
+			output: `╭───────────────────────────────────────────╮
+│ Do you want to proceed?                   │
+╰───────────────────────────────────────────╯
+`,
+			kind: "question",
+			text: "Do you want to proceed?",
+		},
+		{
+			name: "summary",
+			output: `> fix the login bug
+
+⏺ Read(src/login.ts)
+  ⎿  Read 120 lines
+
+⏺ I fixed the session check in src/login.ts.
+` + inputBox,
+			kind: "summary",
+			text: "I fixed the session check in src/login.ts.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractExcerpt(tt.output, 0)
+			if got.Kind != tt.kind || got.Text != tt.text {
+				t.Errorf("ExtractExcerpt() = %+v, want {Kind:%s Text:%s}", got, tt.kind, tt.text)
+			}
+		})
+	}
+}
@@ -5,16 +5,27 @@ import (
 	"time"
 
 	"github.com/bb/gclaude/internal/config"
+	"github.com/bb/gclaude/internal/eventlog"
 	"github.com/bb/gclaude/internal/notify"
 	"github.com/bb/gclaude/internal/session"
 	"github.com/bb/gclaude/internal/tmux"
 )
 
 type sessionState struct {
-	lastOutput    string
+	lastHash      uint64
 	lastChange    time.Time
+	lastCheck     time.Time
 	notified      bool
 	wasActive     bool
+	stalled       bool
+	cpuTicks      uint64
+	lastCPUChange time.Time
+	// Focus detector result for the back-off in due, and when it was taken
+	focused      bool
+	focusChecked time.Time
+	// Last notification sent and how many escalation steps followed it
+	lastEvent  *notify.Event
+	escalation int
 }
 
 type Monitor struct {
@@ -24,18 +35,48 @@ type Monitor struct {
 	wg       sync.WaitGroup
 	states   map[string]*sessionState
 	mu       sync.Mutex
+	norm     *Normalizer
+	notifier *notify.Dispatcher
+	focus    notify.FocusDetector
+	debounce *debouncer
+	// Last state written to disk, to skip redundant writes, and when
+	savedState []byte
+	savedFlags map[string]stateFlags
+	stateSaved time.Time
+	// Reconcile findings already written to the event log
+	reported map[string]bool
+
+	quietMu   sync.Mutex
+	quietHeld []notify.Event
+
+	digestMu    sync.Mutex
+	digestHeld  []heldEvent
+	digestStart time.Time
 }
 
 func New(store *session.Store, cfg *config.Config) *Monitor {
+	dispatcher, errs := notify.NewDispatcher(cfg.Notification)
+	for _, err := range errs {
+		eventlog.Log("notify_error", "", "%v", err)
+	}
+
 	return &Monitor{
 		store:    store,
 		cfg:      cfg,
 		stopChan: make(chan struct{}),
 		states:   make(map[string]*sessionState),
+		norm:     NewNormalizer(cfg.Monitor.Normalize),
+		notifier: dispatcher,
+		focus:    notify.NewFocusDetector(cfg.Notification.FocusDetector),
+		debounce: newDebouncer(time.Duration(cfg.Monitor.DebounceSecs)*time.Second,
+			cfg.Monitor.MaxNotificationsPerMin, time.Now),
 	}
 }
 
 func (m *Monitor) Start() {
+	m.loadStates()
+	m.notifier.SetErrorHandler(logNotifyError)
+	m.notifier.SetActionHandler(m.handleAction)
 	m.wg.Add(1)
 	go m.run()
 }
@@ -43,6 +84,8 @@ func (m *Monitor) Start() {
 func (m *Monitor) Stop() {
 	close(m.stopChan)
 	m.wg.Wait()
+	// Give notifications already queued a chance to go out
+	m.notifier.Close(5 * time.Second)
 }
 
 func (m *Monitor) run() {
@@ -52,108 +95,362 @@ func (m *Monitor) run() {
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
+	m.reconcile()
+	reconcileInterval := time.Duration(m.cfg.Monitor.ReconcileIntervalS) * time.Second
+	if reconcileInterval <= 0 {
+		reconcileInterval = time.Minute
+	}
+	reconcileTicker := time.NewTicker(reconcileInterval)
+	defer reconcileTicker.Stop()
+
 	for {
 		select {
 		case <-m.stopChan:
+			// Held events would be lost; the digest first, as during quiet
+			// hours it's held for the summary
+			now := time.Now()
+			m.flushDigest(now, true)
+			m.flushQuiet(now, true)
+			m.saveStates(now, true)
 			return
+		case <-reconcileTicker.C:
+			m.reconcile()
 		case <-ticker.C:
 			m.checkSessions()
+			m.saveStates(time.Now(), false)
+			m.flushDigest(time.Now(), false)
+			m.flushQuiet(time.Now(), false)
 		}
 	}
 }
 
 func (m *Monitor) checkSessions() {
+	m.store.Reload()
 	sessions := m.store.GetAll()
 
+	// One tmux call for every pane instead of several per session
+	panes, err := tmux.ListPanes()
+	if err != nil {
+		return
+	}
+
+	// Scanned at most once per tick, and only if a session is working
+	procs := sync.OnceValue(readProcTable)
+
+	now := time.Now()
+	var jobs []func()
 	for _, sess := range sessions {
-		if sess.Status == session.StatusStopped {
+		if sess.Status == session.StatusStopped || sess.Status == session.StatusExited {
 			continue
 		}
 
-		exists, err := tmux.SessionExists(sess.TmuxSession)
-		if err != nil || !exists {
-			sess.Status = session.StatusStopped
-			m.store.Update(sess)
+		pane := panes[sess.TmuxSession]
+		if pane != nil && !m.due(sess, pane, now) {
 			continue
 		}
 
-		output, err := tmux.CapturePane(sess.TmuxSession, 100)
-		if err != nil {
-			continue
-		}
+		s := *sess
+		jobs = append(jobs, func() { m.checkSession(&s, pane, procs) })
+	}
+	runPool(m.cfg.Monitor.Workers, jobs)
+}
+
+// runPool runs jobs with at most workers of them at a time and waits for all
+// of them
+func runPool(workers int, jobs []func()) {
+	if workers < 1 {
+		workers = 1
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(job func()) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			job()
+		}(job)
+	}
+	wg.Wait()
+}
+
+// due reports whether a session should be polled this tick. Sessions that are
+// waiting for input, or attached and in front of the user, are polled less
+// often.
+func (m *Monitor) due(sess *session.Session, pane *tmux.PaneInfo, now time.Time) bool {
+	m.mu.Lock()
+	state, ok := m.states[sess.ID]
+	var lastCheck time.Time
+	if ok {
+		lastCheck = state.lastCheck
+	}
+	m.mu.Unlock()
+
+	interval := time.Duration(m.cfg.Monitor.IdlePollIntervalMs) * time.Millisecond
+	if !ok || now.Sub(lastCheck) >= interval {
+		return true
+	}
+
+	if sess.Status == session.StatusWaitingInput || sess.Status == session.StatusRateLimited {
+		return false
+	}
+	return !(pane.Attached && m.focusedCached(state, sess.TmuxSession, now, interval))
+}
+
+// focusedCached runs the focus detector for a session at most once per
+// interval. It forks tmux and the detector, which would cost more every tick
+// than the capture the back-off saves.
+func (m *Monitor) focusedCached(state *sessionState, tmuxSession string, now time.Time, interval time.Duration) bool {
+	m.mu.Lock()
+	focused, checked := state.focused, state.focusChecked
+	m.mu.Unlock()
+	if now.Sub(checked) < interval {
+		return focused
+	}
+
+	focused = notify.IsSessionFocused(m.focus, tmuxSession)
+	m.mu.Lock()
+	state.focused, state.focusChecked = focused, now
+	m.mu.Unlock()
+	return focused
+}
+
+// saveSession writes back the fields the monitor owns. Other fields (mute,
+// priority) may have been changed meanwhile by notification actions or CLI
+// commands and are left alone.
+func (m *Monitor) saveSession(sess *session.Session) {
+	m.store.Modify(sess.ID, func(s *session.Session) {
+		s.Status = sess.Status
+		s.NeedsInput = sess.NeedsInput
+		s.ExitCode = sess.ExitCode
+		s.LastActivity = sess.LastActivity
+		s.RateLimitedUntil = sess.RateLimitedUntil
+		s.ContinueAttempts = sess.ContinueAttempts
+	})
+}
+
+func (m *Monitor) checkSession(sess *session.Session, pane *tmux.PaneInfo, procs func() *procTable) {
+	if pane == nil {
+		sess.Status = session.StatusStopped
+		m.saveSession(sess)
+		return
+	}
+
+	if m.agentExited(&pane.PaneStatus) {
+		sess.Status = session.StatusExited
+		sess.ExitCode = pane.ExitCode
+		sess.NeedsInput = false
+		m.saveSession(sess)
 
 		m.mu.Lock()
-		state, exists := m.states[sess.ID]
-		if !exists {
-			state = &sessionState{
-				lastOutput: output,
-				lastChange: time.Now(),
-				notified:   false,
-				wasActive:  true,
-			}
-			m.states[sess.ID] = state
-			m.mu.Unlock()
-			continue
+		delete(m.states, sess.ID)
+		m.mu.Unlock()
+
+		m.notifyExited(sess)
+		return
+	}
+
+	output, err := tmux.CapturePane(sess.TmuxSession, 100)
+	if err != nil {
+		return
+	}
+	normalized := m.norm.Normalize(output)
+	hash := hashOutput(normalized)
+	working := IsWorking(normalized)
+
+	now := time.Now()
+
+	// Each session is checked by at most one worker per tick, so the lock
+	// only guards the map itself
+	m.mu.Lock()
+	state, exists := m.states[sess.ID]
+	if !exists {
+		m.states[sess.ID] = &sessionState{
+			lastHash:      hash,
+			lastChange:    now,
+			lastCheck:     now,
+			lastCPUChange: now,
+			notified:      false,
+			wasActive:     true,
 		}
+	}
+	m.mu.Unlock()
+	if !exists {
+		return
+	}
+	state.lastCheck = now
 
-		outputChanged := output != state.lastOutput
-		now := time.Now()
-
-		if outputChanged {
-			// Output is changing - Claude is active
-			state.lastOutput = output
-			state.lastChange = now
-			state.notified = false
-			state.wasActive = true
-			sess.UpdateActivity()
-			sess.Status = session.StatusRunning
-			sess.NeedsInput = false
-			m.store.Update(sess)
-		} else {
-			// Output hasn't changed
-			idleTime := now.Sub(state.lastChange)
-			idleThreshold := time.Duration(m.cfg.Monitor.IdleThresholdS) * time.Second
-
-			if idleTime > idleThreshold && state.wasActive && !state.notified {
-				// Claude has stopped - notify user
-				state.notified = true
-				state.wasActive = false
-				sess.Status = session.StatusWaitingInput
-				sess.NeedsInput = true
-				m.store.Update(sess)
-
-				m.notify(sess)
-			}
+	if hash != state.lastHash {
+		// Output is changing - Claude is active
+		state.lastHash = hash
+		state.lastChange = now
+		state.lastCPUChange = now
+		state.notified = false
+		state.lastEvent = nil
+		state.stalled = false
+		state.wasActive = true
+		sess.UpdateActivity()
+		sess.Status = session.StatusRunning
+		sess.NeedsInput = false
+		m.saveSession(sess)
+		return
+	}
+
+	if working {
+		// Claude accepted the last continue, start counting retries afresh
+		if sess.ContinueAttempts > 0 {
+			sess.ContinueAttempts = 0
+			m.saveSession(sess)
 		}
-		m.mu.Unlock()
+
+		// Only volatile content (spinner, timer) is changing - Claude is busy,
+		// but if that goes on for too long it's probably stuck
+		m.sampleCPU(state, procs(), pane.Pid, now)
+		state.wasActive = true
+		m.checkStall(sess, state, now)
+		return
+	}
+
+	if until, kind, ok := DetectLimit(output, now, m.apiErrorRetry()); ok {
+		state.wasActive = false
+		m.handleLimit(sess, until, kind, now)
+		return
 	}
+
+	// Output hasn't changed
+	idleTime := now.Sub(state.lastChange)
+	idleThreshold := time.Duration(m.cfg.Monitor.IdleThresholdS) * time.Second
+
+	if idleTime > idleThreshold && state.wasActive && !state.notified {
+		// Claude has stopped - notify user
+		state.notified = true
+		state.wasActive = false
+		sess.Status = session.StatusWaitingInput
+		sess.NeedsInput = true
+		m.saveSession(sess)
+
+		state.lastEvent = m.notify(sess, output)
+		state.escalation = 0
+		return
+	}
+
+	if state.notified {
+		m.escalate(sess, state, now)
+	}
+}
+
+func (m *Monitor) apiErrorRetry() time.Duration {
+	return time.Duration(m.cfg.Monitor.APIErrorRetryS) * time.Second
 }
 
-func (m *Monitor) notify(sess *session.Session) {
-	// Skip notification if user had recent keyboard input (within idle threshold)
-	// This means user is actively typing/thinking
-	if tmux.HasRecentInput(sess.TmuxSession, m.cfg.Monitor.IdleThresholdS) {
+// handleLimit marks a session as rate limited and, once the limit has reset,
+// optionally types the continue message for it.
+func (m *Monitor) handleLimit(sess *session.Session, until time.Time, kind LimitKind, now time.Time) {
+	if sess.Status != session.StatusRateLimited {
+		sess.Status = session.StatusRateLimited
+		sess.RateLimitedUntil = until
+		sess.NeedsInput = false
+		m.saveSession(sess)
+
+		eventlog.Log(string(kind), sess.Branch, "rate limited until %s", until.Format(time.RFC3339))
+		m.notifyLimited(sess, kind)
 		return
 	}
 
-	// Check if user is actively viewing this session
-	// Skip notification if: attached AND terminal window is focused
-	if tmux.IsSessionAttached(sess.TmuxSession) {
-		tty := tmux.GetAttachedClientTTY(sess.TmuxSession)
-		if tty != "" && notify.IsTerminalFocused(tty) {
-			// User is looking at this session - no notification needed
-			return
+	if !m.cfg.Monitor.AutoContinue || now.Before(sess.RateLimitedUntil) {
+		return
+	}
+
+	if sess.ContinueAttempts >= m.cfg.Monitor.MaxContinueRetries {
+		if sess.ContinueAttempts == m.cfg.Monitor.MaxContinueRetries {
+			sess.ContinueAttempts++
+			m.saveSession(sess)
+			eventlog.Log("auto_continue", sess.Branch, "giving up after %d attempts", m.cfg.Monitor.MaxContinueRetries)
 		}
+		return
 	}
 
-	title := "gclaude: " + sess.Branch
-	message := "Claude has stopped - waiting for input or finished"
+	sess.ContinueAttempts++
+	// Don't retry before the next back-off even if the screen stays the same
+	sess.RateLimitedUntil = now.Add(m.apiErrorRetry() * time.Duration(sess.ContinueAttempts))
+	m.saveSession(sess)
 
-	if m.cfg.Notification.Desktop {
-		notify.Desktop(title, message)
+	err := tmux.SendKeys(sess.TmuxSession, m.cfg.Monitor.ContinueMessage)
+	if err != nil {
+		eventlog.Log("auto_continue", sess.Branch, "attempt %d failed: %v", sess.ContinueAttempts, err)
+		return
 	}
+	eventlog.Log("auto_continue", sess.Branch, "sent %q (attempt %d/%d)",
+		m.cfg.Monitor.ContinueMessage, sess.ContinueAttempts, m.cfg.Monitor.MaxContinueRetries)
+}
 
-	if m.cfg.Notification.Sound {
-		notify.Sound(m.cfg.Notification.SoundFile)
+func (m *Monitor) stallThreshold() time.Duration {
+	return time.Duration(m.cfg.Monitor.StallThresholdS) * time.Second
+}
+
+func (m *Monitor) sampleCPU(state *sessionState, procs *procTable, pid string, now time.Time) {
+	ticks, ok := procs.descendantCPUTicks(pid)
+	if !ok {
+		// No CPU information or no tool running - don't let it count as stalled
+		state.lastCPUChange = now
+		return
+	}
+	if ticks != state.cpuTicks {
+		state.cpuTicks = ticks
+		state.lastCPUChange = now
+	}
+}
+
+// checkStall flags a session that has shown a working indicator without real
+// output for longer than the stall threshold, or whose running tools have
+// used no CPU for that long.
+func (m *Monitor) checkStall(sess *session.Session, state *sessionState, now time.Time) {
+	threshold := m.stallThreshold()
+	if threshold <= 0 || state.stalled {
+		return
+	}
+
+	if now.Sub(state.lastChange) < threshold && now.Sub(state.lastCPUChange) < threshold {
+		return
+	}
+
+	state.stalled = true
+	sess.Status = session.StatusStuck
+	m.saveSession(sess)
+
+	if m.cfg.Monitor.InterruptOnStall {
+		tmux.SendKey(sess.TmuxSession, "Escape")
+	}
+
+	m.notifyStuck(sess, now.Sub(state.lastChange))
+}
+
+// agentExited reports whether the agent process in the pane is gone, either
+// because the pane is dead or because something else replaced it.
+func (m *Monitor) agentExited(pane *tmux.PaneStatus) bool {
+	if pane.Dead {
+		return true
+	}
+	if pane.Pid != "" && !processAlive(pane.Pid) {
+		return true
+	}
+	if len(m.cfg.Monitor.AgentCommands) == 0 || pane.CurrentCommand == "" {
+		return false
+	}
+	// Claude runs tools as children, so only call the agent replaced when
+	// neither the pane's own process nor the foreground command is an agent.
+	return !m.isAgentCommand(pane.CurrentCommand) && !m.isAgentCommand(processName(pane.Pid))
+}
+
+func (m *Monitor) isAgentCommand(name string) bool {
+	if name == "" {
+		return true
+	}
+	for _, c := range m.cfg.Monitor.AgentCommands {
+		if name == c {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bb/gclaude/internal/config"
+	"github.com/bb/gclaude/internal/session"
+	"github.com/bb/gclaude/internal/tmux"
+)
+
+const benchSessions = 20
+
+// fakeTmux puts a tmux on PATH that answers the monitor's queries for
+// benchSessions sessions after a short delay, standing in for the round trip
+// to a real tmux server.
+func fakeTmux(b *testing.B) {
+	b.Helper()
+	dir := b.TempDir()
+	script := fmt.Sprintf(`#!/bin/sh
+sleep 0.002
+case "$1" in
+has-session) exit 0 ;;
+display-message) echo "0  4242 claude" ;;
+list-panes)
+	i=0
+	while [ $i -lt %d ]; do
+		printf 'gclaude-%%d\t11\t0\t0\t\t4242\tclaude\n' $i
+		i=$((i+1))
+	done ;;
+capture-pane) printf '⏺ Done.\n\n> \n' ;;
+esac
+`, benchSessions)
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(script), 0755); err != nil {
+		b.Fatal(err)
+	}
+	b.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+// BenchmarkPollSequential is the polling path before batching: per session,
+// check the session exists, read its pane status and capture it, one after
+// the other.
+func BenchmarkPollSequential(b *testing.B) {
+	fakeTmux(b)
+	for i := 0; i < b.N; i++ {
+		for s := 0; s < benchSessions; s++ {
+			name := fmt.Sprintf("gclaude-%d", s)
+			if ok, err := tmux.SessionExists(name); err != nil || !ok {
+				b.Fatalf("has-session: %v", err)
+			}
+			if err := exec.Command("tmux", "display-message", "-t", name, "-p",
+				"#{pane_dead} #{pane_dead_status} #{pane_pid} #{pane_current_command}").Run(); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := tmux.CapturePane(name, 100); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// BenchmarkPollPooled is the current path: one list-panes for every session,
+// then captures spread over the worker pool.
+func BenchmarkPollPooled(b *testing.B) {
+	fakeTmux(b)
+	for i := 0; i < b.N; i++ {
+		panes, err := tmux.ListPanes()
+		if err != nil || len(panes) != benchSessions {
+			b.Fatalf("list-panes: %d panes, %v", len(panes), err)
+		}
+
+		var jobs []func()
+		for name := range panes {
+			jobs = append(jobs, func() {
+				if _, err := tmux.CapturePane(name, 100); err != nil {
+					b.Error(err)
+				}
+			})
+		}
+		runPool(4, jobs)
+	}
+}
+
+// countingFocus reports every client as focused and counts the checks
+type countingFocus struct {
+	checks int
+}
+
+func (f *countingFocus) Name() string                 { return "counting" }
+func (f *countingFocus) Available() bool              { return true }
+func (f *countingFocus) IsFocused(c tmux.Client) bool { f.checks++; return true }
+
+func TestDueCachesFocus(t *testing.T) {
+	dir := t.TempDir()
+	script := "#!/bin/sh\nprintf '4242\\t/dev/pts/1\\tgclaude-feat\\n'\n"
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &config.Config{}
+	cfg.Monitor.IdlePollIntervalMs = 2000
+	focus := &countingFocus{}
+	m := &Monitor{cfg: cfg, focus: focus, states: make(map[string]*sessionState)}
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	sess := &session.Session{ID: "a", TmuxSession: "gclaude-feat", Status: session.StatusRunning}
+	pane := &tmux.PaneInfo{Attached: true}
+	m.states["a"] = &sessionState{lastCheck: now}
+
+	// Ticks every 500ms within one idle interval: focused, so not due, and
+	// the detector runs once
+	for i := 1; i < 4; i++ {
+		if m.due(sess, pane, now.Add(time.Duration(i)*500*time.Millisecond)) {
+			t.Errorf("tick %d: focused session due before the idle interval", i)
+		}
+	}
+	if focus.checks != 1 {
+		t.Errorf("focus checked %d times, want 1", focus.checks)
+	}
+
+	if !m.due(sess, pane, now.Add(2*time.Second)) {
+		t.Error("session not due after the idle interval")
+	}
+}
@@ -0,0 +1,115 @@
+package monitor
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strings"
+
+	"github.com/bb/gclaude/internal/config"
+)
+
+// Claude Code's status line, redrawn constantly while it works: a spinner,
+// a verb and "(12s · ↑ 1.2k tokens · esc to interrupt)".
+var defaultStatusPatterns = []string{
+	`\(.*esc to interrupt.*\)`,
+	`^\s*[✻✽✶✳✢·*]\s+\S+(…|\.\.\.)`,
+}
+
+// statusTailLines is how far from the bottom status lines are looked for.
+// The status line sits just above the input box; higher up, matching lines
+// are output and their digits count as changes.
+const statusTailLines = 10
+
+var (
+	ansiPattern  = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)|\x1b[@-Z\\-_]`)
+	digitPattern = regexp.MustCompile(`[0-9]+`)
+)
+
+// Spinner glyphs Claude Code cycles through while working.
+var spinnerReplacer = strings.NewReplacer(
+	"✻", "*", "✽", "*", "✶", "*", "✳", "*", "✢", "*", "·", "*",
+	"⠋", "*", "⠙", "*", "⠹", "*", "⠸", "*", "⠼", "*", "⠴", "*", "⠦", "*", "⠧", "*", "⠇", "*", "⠏", "*",
+)
+
+// Normalizer strips volatile content from captured pane output.
+type Normalizer struct {
+	cfg            config.NormalizeConfig
+	statusPatterns []*regexp.Regexp
+}
+
+func NewNormalizer(cfg config.NormalizeConfig) *Normalizer {
+	patterns := cfg.StatusPatterns
+	if len(patterns) == 0 {
+		patterns = defaultStatusPatterns
+	}
+
+	n := &Normalizer{cfg: cfg}
+	for _, p := range patterns {
+		if re, err := regexp.Compile(p); err == nil {
+			n.statusPatterns = append(n.statusPatterns, re)
+		}
+	}
+	return n
+}
+
+// Normalize returns output with ANSI sequences removed, digits in the status
+// lines near the bottom masked, spinner glyphs unified and the last
+// IgnoreTailLines lines dropped.
+func (n *Normalizer) Normalize(output string) string {
+	if n.cfg.StripANSI {
+		output = StripANSI(output)
+	}
+
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	if n.cfg.IgnoreTailLines > 0 {
+		if n.cfg.IgnoreTailLines >= len(lines) {
+			lines = lines[:0]
+		} else {
+			lines = lines[:len(lines)-n.cfg.IgnoreTailLines]
+		}
+	}
+
+	statusFrom := lastNonBlank(lines) - statusTailLines
+	for i, line := range lines {
+		line = strings.TrimRight(line, " ")
+		if n.cfg.MaskDigits && i > statusFrom && n.isStatusLine(line) {
+			line = spinnerReplacer.Replace(line)
+			line = digitPattern.ReplaceAllString(line, "#")
+		}
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// lastNonBlank returns the index of the last line with content, ignoring the
+// empty rows below a prompt at the top of the pane
+func lastNonBlank(lines []string) int {
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+func (n *Normalizer) isStatusLine(line string) bool {
+	for _, re := range n.statusPatterns {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// StripANSI removes terminal escape sequences from s.
+func StripANSI(s string) string {
+	return ansiPattern.ReplaceAllString(s, "")
+}
+
+func hashOutput(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
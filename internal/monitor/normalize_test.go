@@ -0,0 +1,66 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/bb/gclaude/internal/config"
+)
+
+func TestNormalize(t *testing.T) {
+	n := NewNormalizer(config.NormalizeConfig{StripANSI: true, MaskDigits: true})
+
+	tests := []struct {
+		name string
+		a, b string
+		same bool
+	}{
+		{
+			name: "spinner and timer redraw",
+			a:    "⏺ Running tests\n\n✻ Thinking… (12s · ↑ 1.2k tokens · esc to interrupt)\n" + inputBox,
+			b:    "⏺ Running tests\n\n✽ Thinking… (13s · ↑ 1.4k tokens · esc to interrupt)\n" + inputBox,
+			same: true,
+		},
+		{
+			name: "colour only",
+			a:    "\x1b[32mok\x1b[0m\n" + inputBox,
+			b:    "ok\n" + inputBox,
+			same: true,
+		},
+		{
+			name: "test runner durations",
+			a:    "ok  	github.com/bb/gclaude/internal/monitor	3s\n" + inputBox,
+			b:    "ok  	github.com/bb/gclaude/internal/monitor	4s\n" + inputBox,
+		},
+		{
+			name: "log timestamps",
+			a:    "2026/10/18 12:01:02 listening on :8080\n" + inputBox,
+			b:    "2026/10/18 12:01:09 listening on :8080\n" + inputBox,
+		},
+		{
+			name: "token counts in output",
+			a:    "⏺ The prompt uses 1200 tokens\n" + inputBox,
+			b:    "⏺ The prompt uses 1300 tokens\n" + inputBox,
+		},
+		{
+			name: "status line far above the bottom is output",
+			a:    "(3s · esc to interrupt)\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			b:    "(4s · esc to interrupt)\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := n.Normalize(tt.a), n.Normalize(tt.b)
+			if (a == b) != tt.same {
+				t.Errorf("same = %v, want %v\n%q\n%q", a == b, tt.same, a, b)
+			}
+		})
+	}
+}
+
+func TestNormalizeIgnoreTailLines(t *testing.T) {
+	n := NewNormalizer(config.NormalizeConfig{IgnoreTailLines: 2})
+	if got := n.Normalize("a\nb\nc\nd\n"); got != "a\nb" {
+		t.Errorf("Normalize() = %q, want %q", got, "a\nb")
+	}
+}
@@ -0,0 +1,204 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bb/gclaude/internal/eventlog"
+	"github.com/bb/gclaude/internal/inbox"
+	"github.com/bb/gclaude/internal/notify"
+	"github.com/bb/gclaude/internal/session"
+	"github.com/bb/gclaude/internal/tmux"
+)
+
+func (m *Monitor) notifyExited(sess *session.Session) {
+	message := fmt.Sprintf("Claude exited (code %d) - run 'gclaude resume %s' to continue", sess.ExitCode, sess.Branch)
+	m.dispatch(sess, notify.StateExited, notify.UrgencyCritical, message, "")
+}
+
+func (m *Monitor) notifyLimited(sess *session.Session, kind LimitKind) {
+	state := notify.StateRateLimited
+	message := "Claude hit a usage limit until " + sess.RateLimitedUntil.Format("15:04")
+	if kind == LimitAPIError {
+		state = notify.StateError
+		message = "Claude API error - retry after " + sess.RateLimitedUntil.Format("15:04")
+	}
+	if m.cfg.Monitor.AutoContinue {
+		message += " (will continue automatically)"
+	}
+
+	m.dispatch(sess, state, notify.UrgencyNormal, message, "")
+}
+
+func (m *Monitor) notifyStuck(sess *session.Session, since time.Duration) {
+	message := fmt.Sprintf("Claude is possibly stuck - no progress for %s", since.Round(time.Minute))
+	if m.cfg.Monitor.InterruptOnStall {
+		message += " (sent Escape)"
+	}
+
+	m.dispatch(sess, notify.StateStuck, notify.UrgencyCritical, message, "")
+}
+
+// notify reports that Claude stopped, unless the user is already looking at
+// the session. It returns the event sent, or nil.
+func (m *Monitor) notify(sess *session.Session, output string) *notify.Event {
+	// Skip notification if user had recent keyboard input (within idle threshold)
+	// This means user is actively typing/thinking
+	if tmux.HasRecentInput(sess.TmuxSession, m.cfg.Monitor.IdleThresholdS) {
+		return nil
+	}
+
+	// Skip notification if the user is looking at this session
+	if notify.IsSessionFocused(m.focus, sess.TmuxSession) {
+		return nil
+	}
+
+	excerpt := ExtractExcerpt(output, m.cfg.Notification.ExcerptChars)
+
+	state := notify.StateFinished
+	message := "Claude has finished"
+	switch {
+	case excerpt.Kind == "permission":
+		state = notify.StateWaiting
+		message = "Claude needs permission"
+	case excerpt.Kind == "question":
+		state = notify.StateWaiting
+		message = "Claude asked a question"
+	case MatchesInputPattern(lastLines(StripANSI(output), 10)):
+		state = notify.StateWaiting
+		message = "Claude is waiting for input"
+	}
+
+	return m.dispatch(sess, state, notify.UrgencyNormal, message, excerpt.Text)
+}
+
+// dispatch builds an event for sess and delivers it to all notifiers. It
+// returns nil if the session is muted or the notification was debounced.
+func (m *Monitor) dispatch(sess *session.Session, state notify.State, urgency notify.Urgency, message, excerpt string) *notify.Event {
+	if !m.debounce.Allow(sess.ID, state) {
+		eventlog.Log("notify_debounced", sess.Branch, "%s: %s", state, message)
+		return nil
+	}
+
+	ev := notify.Event{
+		Time:         time.Now(),
+		SessionID:    sess.ID,
+		Branch:       sess.Branch,
+		RepoPath:     sess.RepoPath,
+		WorktreePath: sess.WorktreePath,
+		TmuxSession:  sess.TmuxSession,
+		Priority:     string(sess.Priority),
+		State:        state,
+		Urgency:      urgency,
+		Title:        "gclaude: " + sess.Branch,
+		Message:      message,
+		Excerpt:      excerpt,
+	}
+
+	if !m.deliver(ev, nil, false) {
+		return nil
+	}
+
+	if size := m.cfg.Notification.InboxSize; size > 0 {
+		err := inbox.Add(inbox.Item{
+			Time:      ev.Time,
+			SessionID: ev.SessionID,
+			Branch:    ev.Branch,
+			State:     string(ev.State),
+			Message:   ev.Message,
+			Excerpt:   ev.Excerpt,
+		}, size)
+		if err != nil {
+			eventlog.Log("inbox_error", ev.Branch, "%v", err)
+		}
+	}
+	return &ev
+}
+
+// deliver sends ev to the notifier types in allow (all if nil), honouring
+// mute, session priority, digests and quiet hours. Escalations pass
+// escalation so they're never held for the digest. It returns false if the
+// session is muted.
+func (m *Monitor) deliver(ev notify.Event, allow []string, escalation bool) bool {
+	// Mute and priority may have changed since sess was read, by a
+	// notification action or a CLI command
+	if current := m.store.FindByID(ev.SessionID); current != nil {
+		if current.IsMuted(ev.Time) {
+			return false
+		}
+		ev.Priority = string(current.Priority)
+	}
+
+	profile := m.cfg.Notification.Priorities[ev.Priority]
+	if profile.Urgency != "" {
+		ev.Urgency = notify.Urgency(profile.Urgency)
+	}
+	if len(profile.Notifiers) > 0 {
+		allow = intersect(allow, profile.Notifiers)
+	}
+
+	// Escalations bypass the digest
+	if !escalation && m.batch(ev, allow, profile.Digest) {
+		return true
+	}
+
+	m.emit(ev, allow)
+	return true
+}
+
+// emit sends ev now, downgraded and recorded for the summary during quiet hours
+func (m *Monitor) emit(ev notify.Event, allow []string) {
+	if m.quietActive(ev.Time) {
+		m.holdQuiet(ev)
+		ev.Urgency = notify.UrgencyLow
+		allow = intersect(allow, m.cfg.Notification.QuietHours.Allow)
+	}
+
+	m.sendTo(ev, allow)
+}
+
+// intersect returns the types allowed by both lists, where nil allows all
+func intersect(a, b []string) []string {
+	if a == nil {
+		return append([]string{}, b...)
+	}
+	if b == nil {
+		return a
+	}
+	out := []string{}
+	for _, v := range a {
+		for _, w := range b {
+			if v == w {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+func (m *Monitor) send(ev notify.Event) {
+	m.sendTo(ev, nil)
+}
+
+// sendTo queues ev for the allowed notifier types (all if nil). Delivery is
+// asynchronous; failures are logged by logNotifyError.
+func (m *Monitor) sendTo(ev notify.Event, allow []string) {
+	m.notifier.SendTo(ev, allow)
+}
+
+func logNotifyError(ev notify.Event, err error) {
+	eventlog.Log("notify_error", ev.Branch, "%v", err)
+}
+
+// lastLines returns the last n non-empty lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(s, "\n")
+	var out []string
+	for i := len(lines) - 1; i >= 0 && len(out) < n; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			out = append([]string{line}, out...)
+		}
+	}
+	return strings.Join(out, "\n")
+}
@@ -1,6 +1,9 @@
 package monitor
 
-import "regexp"
+import (
+	"regexp"
+	"strings"
+)
 
 var defaultPatterns = []string{
 	// Yes/No prompts
@@ -37,6 +40,12 @@ var defaultPatterns = []string{
 	`Allow all`,
 }
 
+// Indicators Claude Code shows while it is busy working on a turn
+var workingPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`esc to interrupt`),
+	regexp.MustCompile(`ctrl\+c to interrupt`),
+}
+
 var compiledPatterns []*regexp.Regexp
 
 func init() {
@@ -74,3 +83,18 @@ func AddPattern(pattern string) error {
 	compiledPatterns = append(compiledPatterns, re)
 	return nil
 }
+
+// IsWorking reports whether the bottom of the pane shows a busy indicator.
+func IsWorking(output string) bool {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > 10 {
+		lines = lines[len(lines)-10:]
+	}
+	tail := strings.Join(lines, "\n")
+	for _, re := range workingPatterns {
+		if re.MatchString(tail) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bb/gclaude/internal/procfs"
+)
+
+func processAlive(pid string) bool {
+	if _, err := os.Stat("/proc/self"); err != nil {
+		// No procfs - can't tell, assume alive
+		return true
+	}
+	_, err := os.Stat(filepath.Join("/proc", pid))
+	return !os.IsNotExist(err)
+}
+
+func processName(pid string) string {
+	data, err := os.ReadFile(filepath.Join("/proc", pid, "comm"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// procTable is every process's parent and CPU time, read from /proc once per
+// tick and shared by the workers
+type procTable struct {
+	children map[string][]string
+	ticks    map[string]uint64
+}
+
+// readProcTable scans /proc. Returns nil when procfs isn't available.
+func readProcTable() *procTable {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	t := &procTable{
+		children: make(map[string][]string),
+		ticks:    make(map[string]uint64),
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if name[0] < '0' || name[0] > '9' {
+			continue
+		}
+		ppid, ticks, ok := procfs.ReadStat(name)
+		if !ok {
+			continue
+		}
+		t.children[ppid] = append(t.children[ppid], name)
+		t.ticks[name] = ticks
+	}
+	return t
+}
+
+// descendantCPUTicks sums CPU time used by the descendants of pid - the
+// tools the agent runs - but not pid itself, which redraws its spinner
+// constantly. Returns ok=false when pid isn't in the table or has no children.
+func (t *procTable) descendantCPUTicks(pid string) (uint64, bool) {
+	if t == nil {
+		return 0, false
+	}
+	if _, ok := t.ticks[pid]; !ok || len(t.children[pid]) == 0 {
+		return 0, false
+	}
+
+	var total uint64
+	queue := append([]string{}, t.children[pid]...)
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		total += t.ticks[p]
+		queue = append(queue, t.children[p]...)
+	}
+	return total, true
+}
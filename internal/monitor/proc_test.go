@@ -0,0 +1,38 @@
+package monitor
+
+import "testing"
+
+func TestDescendantCPUTicks(t *testing.T) {
+	// 100 is the agent, running a shell (200) that runs a test (300, 301)
+	table := &procTable{
+		children: map[string][]string{
+			"1":   {"100", "500"},
+			"100": {"200"},
+			"200": {"300", "301"},
+		},
+		ticks: map[string]uint64{"100": 9000, "200": 5, "300": 40, "301": 2, "500": 7, "600": 3},
+	}
+
+	tests := []struct {
+		pid   string
+		ticks uint64
+		ok    bool
+	}{
+		{"100", 47, true},
+		{"200", 42, true},
+		{"300", 0, false}, // no children
+		{"600", 0, false}, // no children
+		{"999", 0, false}, // unknown
+	}
+	for _, tt := range tests {
+		ticks, ok := table.descendantCPUTicks(tt.pid)
+		if ticks != tt.ticks || ok != tt.ok {
+			t.Errorf("descendantCPUTicks(%s) = %d, %v, want %d, %v", tt.pid, ticks, ok, tt.ticks, tt.ok)
+		}
+	}
+
+	var missing *procTable
+	if _, ok := missing.descendantCPUTicks("100"); ok {
+		t.Error("nil table reported CPU time")
+	}
+}
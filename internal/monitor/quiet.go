@@ -0,0 +1,55 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bb/gclaude/internal/dnd"
+	"github.com/bb/gclaude/internal/notify"
+)
+
+// quietActive reports whether do-not-disturb or quiet hours are in effect
+func (m *Monitor) quietActive(now time.Time) bool {
+	return dnd.Active(m.cfg.Notification.QuietHours, now)
+}
+
+// holdQuiet records an event that arrived during a quiet period, for the
+// summary sent once it ends.
+func (m *Monitor) holdQuiet(ev notify.Event) {
+	m.quietMu.Lock()
+	defer m.quietMu.Unlock()
+	m.quietHeld = append(m.quietHeld, ev)
+}
+
+// flushQuiet sends a summary of held events once the quiet period is over,
+// or right away with force (on shutdown)
+func (m *Monitor) flushQuiet(now time.Time, force bool) {
+	m.quietMu.Lock()
+	if len(m.quietHeld) == 0 || (!force && m.quietActive(now)) {
+		m.quietMu.Unlock()
+		return
+	}
+	held := m.quietHeld
+	m.quietHeld = nil
+	m.quietMu.Unlock()
+
+	m.sendSummary(held, "while in do-not-disturb", now)
+}
+
+// sendSummary delivers one notification listing several held events
+func (m *Monitor) sendSummary(held []notify.Event, reason string, now time.Time) {
+	var lines []string
+	for _, ev := range held {
+		lines = append(lines, fmt.Sprintf("%s: %s (%s)", ev.Branch, ev.State, ev.Time.Format("15:04")))
+	}
+
+	ev := notify.Event{
+		Time:    now,
+		State:   notify.StateSummary,
+		Urgency: notify.UrgencyNormal,
+		Title:   fmt.Sprintf("gclaude: %d notifications %s", len(held), reason),
+		Message: strings.Join(lines, "\n"),
+	}
+	m.send(ev)
+}
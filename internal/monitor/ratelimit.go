@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Claude Code prints these as whole lines, possibly under a "⎿" tool result
+// marker. Anchoring them keeps ordinary output that mentions rate limits or
+// overloads from pausing a session.
+var (
+	limitPattern = regexp.MustCompile(`(?im)^(?:⎿\s*)?(?:Claude (?:AI )?usage limit reached|(?:\d+-hour|weekly|opus weekly|session) limit reached|You've (?:hit|reached) your (?:usage )?limit)`)
+	// "resets 3pm", "reset at 3:30 pm (Europe/Berlin)", "resets 15:00"
+	resetPattern = regexp.MustCompile(`(?i)resets?\s+(?:at\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?(?:\s*\(([^)]+)\))?`)
+	// Older Claude builds print "Claude AI usage limit reached|<unix time>"
+	resetEpochPattern = regexp.MustCompile(`limit reached\|(\d{10})`)
+	apiErrorPattern   = regexp.MustCompile(`(?m)^(?:⎿\s*)?API Error:?\s*\(?(?:429|5\d\d)\b`)
+)
+
+// LimitKind identifies why a session can't make progress
+type LimitKind string
+
+const (
+	LimitUsage    LimitKind = "usage_limit"
+	LimitAPIError LimitKind = "api_error"
+)
+
+// limitTailLines is how many non-empty lines at the bottom of the pane are
+// searched: the error line plus Claude Code's input box and status line
+const limitTailLines = 8
+
+// DetectLimit looks for a usage-limit or API error line at the bottom of the
+// pane and returns when it's worth trying again. apiRetry is used for errors
+// that carry no reset time.
+func DetectLimit(output string, now time.Time, apiRetry time.Duration) (time.Time, LimitKind, bool) {
+	tail := lastLines(StripANSI(output), limitTailLines)
+
+	if limitPattern.MatchString(tail) {
+		if m := resetEpochPattern.FindStringSubmatch(tail); m != nil {
+			sec, _ := strconv.ParseInt(m[1], 10, 64)
+			return time.Unix(sec, 0), LimitUsage, true
+		}
+		if m := resetPattern.FindStringSubmatch(tail); m != nil {
+			if until, ok := parseResetTime(m, now); ok {
+				return until, LimitUsage, true
+			}
+		}
+		return now.Add(apiRetry), LimitUsage, true
+	}
+
+	if apiErrorPattern.MatchString(tail) {
+		return now.Add(apiRetry), LimitAPIError, true
+	}
+
+	return time.Time{}, "", false
+}
+
+// parseResetTime turns a resetPattern match into the next matching wall-clock
+// time after now.
+func parseResetTime(m []string, now time.Time) (time.Time, bool) {
+	hour, err := strconv.Atoi(m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	minute := 0
+	if m[2] != "" {
+		minute, _ = strconv.Atoi(m[2])
+	}
+
+	switch strings.ToLower(m[3]) {
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return time.Time{}, false
+	}
+
+	loc := now.Location()
+	if m[4] != "" {
+		if l, err := time.LoadLocation(m[4]); err == nil {
+			loc = l
+		}
+	}
+
+	local := now.In(loc)
+	until := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, loc)
+	if !until.After(now) {
+		until = until.Add(24 * time.Hour)
+	}
+	return until, true
+}
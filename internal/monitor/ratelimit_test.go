@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+const inputBox = `
+╭──────────────────────────────────────────╮
+│ >                                        │
+╰──────────────────────────────────────────╯
+  ? for shortcuts
+`
+
+func TestDetectLimit(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	retry := 5 * time.Minute
+
+	tests := []struct {
+		name   string
+		output string
+		kind   LimitKind
+		until  time.Time
+	}{
+		{
+			name:   "usage limit with reset time",
+			output: "⏺ Working on it\n\nClaude usage limit reached. Your limit will reset at 3pm (UTC).\n" + inputBox,
+			kind:   LimitUsage,
+			until:  time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "usage limit with epoch",
+			output: "Claude AI usage limit reached|1792339200\n" + inputBox,
+			kind:   LimitUsage,
+			until:  time.Unix(1792339200, 0),
+		},
+		{
+			name:   "five hour limit",
+			output: "5-hour limit reached ∙ resets 1pm\n" + inputBox,
+			kind:   LimitUsage,
+			until:  time.Date(2026, 10, 18, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "api error under tool result",
+			output: "⏺ Bash(npm test)\n  ⎿  API Error: 529 {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\"}}\n" + inputBox,
+			kind:   LimitAPIError,
+			until:  now.Add(retry),
+		},
+		{
+			name:   "claude describing rate limiting",
+			output: "⏺ I added a rate limit middleware; requests fail once the limit reached 100/min.\n" + inputBox,
+		},
+		{
+			name:   "test name mentioning overload",
+			output: "  ✓ TestServerOverloaded (0.01s)\n  ✓ returns API Error: 503 when overloaded\n" + inputBox,
+		},
+		{
+			name:   "old error scrolled away",
+			output: "API Error: 500 internal\n" + "⏺ Retried and it worked.\n1\n2\n3\n4\n5\n6\n7\n" + inputBox,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, kind, ok := DetectLimit(tt.output, now, retry)
+			if ok != (tt.kind != "") {
+				t.Fatalf("DetectLimit() ok = %v, want %v", ok, tt.kind != "")
+			}
+			if kind != tt.kind {
+				t.Errorf("kind = %q, want %q", kind, tt.kind)
+			}
+			if ok && !until.Equal(tt.until) {
+				t.Errorf("until = %v, want %v", until, tt.until)
+			}
+		})
+	}
+}
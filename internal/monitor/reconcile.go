@@ -0,0 +1,37 @@
+package monitor
+
+import (
+	"github.com/bb/gclaude/internal/eventlog"
+	"github.com/bb/gclaude/internal/session"
+)
+
+// reconcile compares the store against tmux and logs anything new it finds.
+// Orphans and missing worktrees are only logged the first time they're seen.
+func (m *Monitor) reconcile() {
+	report, err := session.Reconcile(m.store)
+	if err != nil {
+		eventlog.Log("reconcile", "", "failed: %v", err)
+		return
+	}
+
+	for _, sess := range report.MarkedStopped {
+		eventlog.Log("reconcile", sess.Branch, "tmux session %s is gone, marked stopped", sess.TmuxSession)
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range report.Orphans {
+		key := "orphan:" + name
+		seen[key] = true
+		if !m.reported[key] {
+			eventlog.Log("reconcile", "", "tmux session %s has no store entry", name)
+		}
+	}
+	for _, sess := range report.MissingWorktrees {
+		key := "worktree:" + sess.ID
+		seen[key] = true
+		if !m.reported[key] {
+			eventlog.Log("reconcile", sess.Branch, "worktree %s no longer exists", sess.WorktreePath)
+		}
+	}
+	m.reported = seen
+}
@@ -0,0 +1,127 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bb/gclaude/internal/config"
+)
+
+// persistedState is the part of sessionState that survives daemon restarts
+type persistedState struct {
+	Hash       uint64    `json:"hash"`
+	LastChange time.Time `json:"last_change"`
+	Notified   bool      `json:"notified"`
+	WasActive  bool      `json:"was_active"`
+	Stalled    bool      `json:"stalled,omitempty"`
+}
+
+// stateFlags are the fields whose changes are saved right away; the rest
+// (hash, last change) move with every bit of output and are saved at most
+// every stateSaveInterval
+type stateFlags struct {
+	Notified, WasActive, Stalled bool
+}
+
+const stateSaveInterval = 5 * time.Second
+
+func (ps persistedState) flags() stateFlags {
+	return stateFlags{ps.Notified, ps.WasActive, ps.Stalled}
+}
+
+func statePath() string {
+	return filepath.Join(config.GetDataDir(), "monitor-state.json")
+}
+
+// loadStates restores per-session state saved by a previous daemon, dropping
+// entries for sessions that no longer exist.
+func (m *Monitor) loadStates() {
+	data, err := os.ReadFile(statePath())
+	if err != nil {
+		return
+	}
+
+	var saved map[string]persistedState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return
+	}
+
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.savedFlags = make(map[string]stateFlags)
+	for id, ps := range saved {
+		if m.store.FindByID(id) == nil {
+			continue
+		}
+		m.states[id] = &sessionState{
+			lastHash:      ps.Hash,
+			lastChange:    ps.LastChange,
+			notified:      ps.Notified,
+			wasActive:     ps.WasActive,
+			stalled:       ps.Stalled,
+			lastCPUChange: now,
+		}
+		m.savedFlags[id] = ps.flags()
+	}
+	m.savedState = data
+	m.stateSaved = now
+}
+
+// saveStates writes the current per-session state to the data dir if it
+// changed since the last save: right away when a session was notified,
+// became active or stalled, or when force is set, otherwise at most every
+// stateSaveInterval.
+func (m *Monitor) saveStates(now time.Time, force bool) error {
+	m.mu.Lock()
+	snapshot := make(map[string]persistedState, len(m.states))
+	for id, st := range m.states {
+		if m.store.FindByID(id) == nil {
+			delete(m.states, id)
+			continue
+		}
+		snapshot[id] = persistedState{
+			Hash:       st.lastHash,
+			LastChange: st.lastChange,
+			Notified:   st.notified,
+			WasActive:  st.wasActive,
+			Stalled:    st.stalled,
+		}
+	}
+	m.mu.Unlock()
+
+	flags := make(map[string]stateFlags, len(snapshot))
+	for id, ps := range snapshot {
+		flags[id] = ps.flags()
+	}
+	if !force && maps.Equal(flags, m.savedFlags) && now.Sub(m.stateSaved) < stateSaveInterval {
+		return nil
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, m.savedState) {
+		return nil
+	}
+
+	if err := config.EnsureDataDir(); err != nil {
+		return err
+	}
+	tmp := statePath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, statePath()); err != nil {
+		return err
+	}
+	m.savedState = data
+	m.savedFlags = flags
+	m.stateSaved = now
+	return nil
+}
@@ -0,0 +1,60 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bb/gclaude/internal/session"
+)
+
+func TestSaveStatesThrottled(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	store := session.GetStore()
+	if err := store.Add(&session.Session{ID: "a", Branch: "feat"}); err != nil {
+		t.Fatal(err)
+	}
+	m := &Monitor{store: store, states: map[string]*sessionState{"a": {}}}
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	saved := func() persistedState {
+		t.Helper()
+		m2 := &Monitor{store: store, states: make(map[string]*sessionState)}
+		m2.loadStates()
+		st, ok := m2.states["a"]
+		if !ok {
+			return persistedState{}
+		}
+		return persistedState{Hash: st.lastHash, Notified: st.notified}
+	}
+
+	m.saveStates(now, false)
+
+	// Streaming output: hash changes every tick, written at most every
+	// stateSaveInterval
+	m.states["a"].lastHash = 1
+	m.saveStates(now.Add(500*time.Millisecond), false)
+	if got := saved().Hash; got != 0 {
+		t.Errorf("output change saved after 500ms (hash %d)", got)
+	}
+	m.saveStates(now.Add(stateSaveInterval), false)
+	if got := saved().Hash; got != 1 {
+		t.Errorf("output change not saved after %v", stateSaveInterval)
+	}
+
+	// A notification is saved right away
+	m.states["a"].notified = true
+	m.states["a"].lastHash = 2
+	m.saveStates(now.Add(stateSaveInterval+time.Second), false)
+	if got := saved(); !got.Notified || got.Hash != 2 {
+		t.Errorf("transition not saved right away: %+v", got)
+	}
+
+	// And everything on stop
+	m.states["a"].lastHash = 3
+	m.saveStates(now.Add(stateSaveInterval+2*time.Second), true)
+	if got := saved().Hash; got != 3 {
+		t.Errorf("forced save wrote hash %d, want 3", got)
+	}
+}
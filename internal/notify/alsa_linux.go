@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+// Playing through the kernel's ALSA PCM interface lets gclaude make a sound
+// on machines without any audio tools installed. It only works when no sound
+// server holds the device; otherwise the players in playPCM are used.
+
+// sndHwParams mirrors struct snd_pcm_hw_params from <sound/asound.h>
+type sndHwParams struct {
+	flags     uint32
+	masks     [3][8]uint32
+	mres      [5][8]uint32
+	intervals [12]sndInterval
+	ires      [9]sndInterval
+	rmask     uint32
+	cmask     uint32
+	info      uint32
+	msbits    uint32
+	rateNum   uint32
+	rateDen   uint32
+	fifoSize  uintptr
+	reserved  [64]byte
+}
+
+type sndInterval struct {
+	min, max, flags uint32
+}
+
+const (
+	sndParamAccess    = 0
+	sndParamFormat    = 1
+	sndParamSubformat = 2
+	// Interval parameters are numbered after the masks
+	sndParamChannels = 10 - 8
+	sndParamRate     = 11 - 8
+
+	sndAccessRWInterleaved = 3
+	sndFormatS16LE         = 2
+	sndSubformatStd        = 0
+)
+
+func sndIoctl(dir, nr, size uintptr) uintptr {
+	return dir<<30 | size<<16 | 'A'<<8 | nr
+}
+
+var (
+	sndIoctlHwParams = sndIoctl(3, 0x11, unsafe.Sizeof(sndHwParams{}))
+	sndIoctlPrepare  = sndIoctl(0, 0x40, 0)
+	sndIoctlDrain    = sndIoctl(0, 0x44, 0)
+)
+
+func ioctl(fd int, req uintptr, arg unsafe.Pointer) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), req, uintptr(arg)); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// playALSA writes clip to the first playback device that can be opened
+func playALSA(clip *pcm) error {
+	devices, _ := filepath.Glob("/dev/snd/pcmC*D*p")
+	if len(devices) == 0 {
+		return fmt.Errorf("no ALSA playback device")
+	}
+
+	var err error
+	for _, dev := range devices {
+		if err = playALSADevice(dev, clip); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func playALSADevice(dev string, clip *pcm) error {
+	// Non-blocking, so a device held by a sound server fails instead of
+	// waiting for it
+	fd, err := syscall.Open(dev, syscall.O_WRONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(fd)
+	if err := syscall.SetNonblock(fd, false); err != nil {
+		return err
+	}
+
+	// Start from "any configuration" and restrict to interleaved S16_LE with
+	// one or two channels at 44.1 or 48 kHz; the kernel picks the rest.
+	var p sndHwParams
+	for i := range p.masks {
+		for j := range p.masks[i] {
+			p.masks[i][j] = ^uint32(0)
+		}
+	}
+	for i := range p.intervals {
+		p.intervals[i] = sndInterval{min: 0, max: ^uint32(0)}
+	}
+	p.rmask = ^uint32(0)
+	p.info = ^uint32(0)
+	p.masks[sndParamAccess] = [8]uint32{1 << sndAccessRWInterleaved}
+	p.masks[sndParamFormat] = [8]uint32{1 << sndFormatS16LE}
+	p.masks[sndParamSubformat] = [8]uint32{1 << sndSubformatStd}
+	p.intervals[sndParamChannels] = sndInterval{min: 1, max: 2}
+	p.intervals[sndParamRate] = sndInterval{min: 44100, max: 48000}
+
+	if err := ioctl(fd, sndIoctlHwParams, unsafe.Pointer(&p)); err != nil {
+		return fmt.Errorf("%s: hw params: %w", dev, err)
+	}
+	if err := ioctl(fd, sndIoctlPrepare, nil); err != nil {
+		return fmt.Errorf("%s: prepare: %w", dev, err)
+	}
+
+	channels := int(p.intervals[sndParamChannels].min)
+	rate := int(p.intervals[sndParamRate].min)
+	data := clip.resampled(rate).frames(channels)
+
+	for len(data) > 0 {
+		n, err := syscall.Write(fd, data)
+		if err != nil {
+			return fmt.Errorf("%s: write: %w", dev, err)
+		}
+		data = data[n:]
+	}
+	return ioctl(fd, sndIoctlDrain, nil)
+}
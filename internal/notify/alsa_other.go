@@ -0,0 +1,9 @@
+//go:build !linux
+
+package notify
+
+import "fmt"
+
+func playALSA(clip *pcm) error {
+	return fmt.Errorf("ALSA is only available on Linux")
+}
@@ -0,0 +1,221 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// The dbus notifier talks to org.freedesktop.Notifications on the session
+// bus, so notifications can carry action buttons and replace each other per
+// session.
+
+const (
+	dbusDest      = "org.freedesktop.Notifications"
+	dbusPath      = dbus.ObjectPath("/org/freedesktop/Notifications")
+	dbusInterface = "org.freedesktop.Notifications"
+
+	dbusCallTimeout = 5 * time.Second
+)
+
+func init() {
+	Register("dbus", func(options json.RawMessage) (Notifier, error) {
+		opts := dbusOptions{Actions: true, TimeoutMs: -1}
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return newDBus(opts)
+	})
+}
+
+// newDBus connects to the session bus and checks a notification server is
+// there, starting it if it's D-Bus activated
+func newDBus(opts dbusOptions) (*dbusNotifier, error) {
+	d := &dbusNotifier{
+		opts:     opts,
+		ids:      make(map[string]uint32),
+		sessions: make(map[uint32]Event),
+	}
+	conn, err := d.connection()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbusCallTimeout)
+	defer cancel()
+	var name, vendor, version, spec string
+	err = conn.Object(dbusDest, dbusPath).CallWithContext(ctx, dbusInterface+".GetServerInformation", 0).
+		Store(&name, &vendor, &version, &spec)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("dbus: no notification server: %w", err)
+	}
+	return d, nil
+}
+
+type dbusOptions struct {
+	// Actions adds Attach / Snooze / Mute buttons
+	Actions bool `json:"actions"`
+	// TimeoutMs is the expiry passed to the server, -1 for its default
+	TimeoutMs int `json:"timeout_ms"`
+}
+
+type dbusNotifier struct {
+	opts dbusOptions
+
+	mu       sync.Mutex
+	conn     *dbus.Conn
+	ids      map[string]uint32 // session ID -> notification ID
+	sessions map[uint32]Event  // notification ID -> last event
+	handler  ActionHandler
+}
+
+var (
+	dbusUrgencies         = map[Urgency]byte{UrgencyLow: 0, UrgencyNormal: 1, UrgencyCritical: 2}
+	dbusActionDefinitions = []string{
+		"default", "Attach",
+		ActionAttach, "Attach",
+		ActionSnooze, "Snooze 10m",
+		ActionMute, "Mute session",
+	}
+)
+
+func (d *dbusNotifier) Name() string { return "dbus" }
+
+// connection returns the session bus connection, reconnecting if the bus went
+// away. A new connection subscribes to the server's signals.
+func (d *dbusNotifier) connection() (*dbus.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.conn != nil && d.conn.Connected() {
+		return d.conn, nil
+	}
+
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("dbus: %w", err)
+	}
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchSender(dbusDest),
+		dbus.WithMatchObjectPath(dbusPath),
+		dbus.WithMatchInterface(dbusInterface),
+	); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("dbus: %w", err)
+	}
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	go d.listen(signals)
+
+	// IDs from a previous connection may belong to another server
+	d.conn = conn
+	d.ids = make(map[string]uint32)
+	d.sessions = make(map[uint32]Event)
+	return conn, nil
+}
+
+func (d *dbusNotifier) Notify(ev Event) error {
+	conn, err := d.connection()
+	if err != nil {
+		return err
+	}
+
+	// Summaries neither replace each other nor get actions
+	var replaces uint32
+	if !ev.IsSummary() {
+		d.mu.Lock()
+		replaces = d.ids[ev.SessionID]
+		d.mu.Unlock()
+	}
+
+	actions := []string{}
+	if d.opts.Actions && !ev.IsSummary() {
+		actions = dbusActionDefinitions
+	}
+
+	urgency, ok := dbusUrgencies[ev.Urgency]
+	if !ok {
+		urgency = 1
+	}
+	hints := map[string]dbus.Variant{
+		"urgency":       dbus.MakeVariant(urgency),
+		"desktop-entry": dbus.MakeVariant("gclaude"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbusCallTimeout)
+	defer cancel()
+
+	var id uint32
+	err = conn.Object(dbusDest, dbusPath).CallWithContext(ctx, dbusInterface+".Notify", 0,
+		"gclaude", replaces, "", ev.Title, ev.Body(), actions, hints, int32(d.opts.TimeoutMs),
+	).Store(&id)
+	if err != nil {
+		return fmt.Errorf("dbus: %w", err)
+	}
+
+	if ev.IsSummary() {
+		return nil
+	}
+	d.mu.Lock()
+	delete(d.sessions, replaces)
+	d.ids[ev.SessionID] = id
+	d.sessions[id] = ev
+	d.mu.Unlock()
+	return nil
+}
+
+// SetActionHandler sets the function called for clicked actions
+func (d *dbusNotifier) SetActionHandler(h ActionHandler) {
+	d.mu.Lock()
+	d.handler = h
+	d.mu.Unlock()
+}
+
+// listen handles the server's signals until the connection closes
+func (d *dbusNotifier) listen(signals <-chan *dbus.Signal) {
+	for sig := range signals {
+		d.handleSignal(sig)
+	}
+}
+
+func (d *dbusNotifier) handleSignal(sig *dbus.Signal) {
+	switch sig.Name {
+	case dbusInterface + ".NotificationClosed":
+		var id, reason uint32
+		if dbus.Store(sig.Body, &id, &reason) != nil {
+			return
+		}
+		d.mu.Lock()
+		if ev, ok := d.sessions[id]; ok {
+			delete(d.sessions, id)
+			if d.ids[ev.SessionID] == id {
+				delete(d.ids, ev.SessionID)
+			}
+		}
+		d.mu.Unlock()
+
+	case dbusInterface + ".ActionInvoked":
+		var id uint32
+		var action string
+		if dbus.Store(sig.Body, &id, &action) != nil {
+			return
+		}
+		if action == "default" {
+			action = ActionAttach
+		}
+
+		d.mu.Lock()
+		ev, ok := d.sessions[id]
+		h := d.handler
+		d.mu.Unlock()
+
+		if ok && h != nil {
+			h(ev, action)
+		}
+	}
+}
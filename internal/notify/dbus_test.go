@@ -0,0 +1,260 @@
+package notify
+
+import (
+	"bufio"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// sessionBus starts a private dbus-daemon and points the session bus at it
+func sessionBus(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("dbus-daemon"); err != nil {
+		t.Skip("dbus-daemon not installed")
+	}
+
+	cmd := exec.Command("dbus-daemon", "--session", "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	addr, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading bus address: %v", err)
+	}
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", strings.TrimSpace(addr))
+}
+
+// notifyCall is one Notify call seen by fakeServer
+type notifyCall struct {
+	Replaces uint32
+	Summary  string
+	Actions  []string
+	Urgency  byte
+}
+
+// fakeServer implements the Notify method of org.freedesktop.Notifications
+type fakeServer struct {
+	conn *dbus.Conn
+
+	mu     sync.Mutex
+	nextID uint32
+	calls  []notifyCall
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	s := &fakeServer{conn: conn}
+	if err := conn.ExportMethodTable(map[string]any{
+		"Notify":               s.Notify,
+		"GetServerInformation": s.GetServerInformation,
+	}, dbusPath, dbusInterface); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := conn.RequestName(dbusDest, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("RequestName: %v, %v", reply, err)
+	}
+	return s
+}
+
+func (s *fakeServer) Notify(app string, replaces uint32, icon, summary, body string,
+	actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var urgency byte
+	hints["urgency"].Store(&urgency)
+	s.calls = append(s.calls, notifyCall{Replaces: replaces, Summary: summary, Actions: actions, Urgency: urgency})
+
+	if replaces != 0 {
+		return replaces, nil
+	}
+	s.nextID++
+	return s.nextID, nil
+}
+
+func (s *fakeServer) GetServerInformation() (string, string, string, string, *dbus.Error) {
+	return "fake", "gclaude", "1", "1.2", nil
+}
+
+func (s *fakeServer) lastCall(t *testing.T) notifyCall {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.calls) == 0 {
+		t.Fatal("no Notify calls")
+	}
+	return s.calls[len(s.calls)-1]
+}
+
+func (s *fakeServer) emit(t *testing.T, signal string, args ...any) {
+	t.Helper()
+	if err := s.conn.Emit(dbusPath, dbusInterface+"."+signal, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newTestDBus(t *testing.T) *dbusNotifier {
+	t.Helper()
+	n, err := registry["dbus"](nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := n.(*dbusNotifier)
+	t.Cleanup(func() { d.conn.Close() })
+	return d
+}
+
+func TestDBusReplacesPerSession(t *testing.T) {
+	sessionBus(t)
+	server := newFakeServer(t)
+	d := newTestDBus(t)
+
+	send := func(ev Event) notifyCall {
+		t.Helper()
+		if err := d.Notify(ev); err != nil {
+			t.Fatal(err)
+		}
+		return server.lastCall(t)
+	}
+
+	first := send(Event{SessionID: "a", Title: "one", Urgency: UrgencyCritical})
+	if first.Replaces != 0 || first.Summary != "one" || first.Urgency != 2 {
+		t.Errorf("first call = %+v", first)
+	}
+	if len(first.Actions) != len(dbusActionDefinitions) {
+		t.Errorf("actions = %v", first.Actions)
+	}
+
+	if got := send(Event{SessionID: "a", Title: "two"}); got.Replaces != 1 {
+		t.Errorf("second call for the session replaces %d, want 1", got.Replaces)
+	}
+	if got := send(Event{SessionID: "b", Title: "other"}); got.Replaces != 0 {
+		t.Errorf("other session replaces %d, want 0", got.Replaces)
+	}
+
+	// Once the user closes it, the next one is new
+	server.emit(t, "NotificationClosed", uint32(1), uint32(2))
+	waitFor(t, func() bool {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		_, ok := d.ids["a"]
+		return !ok
+	})
+	if got := send(Event{SessionID: "a", Title: "three"}); got.Replaces != 0 {
+		t.Errorf("call after close replaces %d, want 0", got.Replaces)
+	}
+}
+
+func TestDBusActions(t *testing.T) {
+	sessionBus(t)
+	server := newFakeServer(t)
+	d := newTestDBus(t)
+
+	type invoked struct {
+		session, action string
+	}
+	got := make(chan invoked, 4)
+	d.SetActionHandler(func(ev Event, action string) {
+		got <- invoked{ev.SessionID, action}
+	})
+
+	if err := d.Notify(Event{SessionID: "a", Title: "waiting"}); err != nil {
+		t.Fatal(err)
+	}
+
+	server.emit(t, "ActionInvoked", uint32(1), ActionSnooze)
+	server.emit(t, "ActionInvoked", uint32(1), "default")
+	// Not one of ours
+	server.emit(t, "ActionInvoked", uint32(99), ActionMute)
+
+	for _, want := range []invoked{{"a", ActionSnooze}, {"a", ActionAttach}} {
+		select {
+		case inv := <-got:
+			if inv != want {
+				t.Errorf("handler got %+v, want %+v", inv, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("handler not called for %+v", want)
+		}
+	}
+	select {
+	case inv := <-got:
+		t.Errorf("handler called for unknown notification: %+v", inv)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDBusSummaryHasNoActions(t *testing.T) {
+	sessionBus(t)
+	server := newFakeServer(t)
+	d := newTestDBus(t)
+
+	for i := 0; i < 2; i++ {
+		if err := d.Notify(Event{Title: "digest"}); err != nil {
+			t.Fatal(err)
+		}
+		call := server.lastCall(t)
+		if call.Replaces != 0 || len(call.Actions) != 0 {
+			t.Errorf("summary %d: replaces %d, actions %v", i, call.Replaces, call.Actions)
+		}
+	}
+	if len(d.ids) != 0 || len(d.sessions) != 0 {
+		t.Errorf("summaries were tracked: ids %v", d.ids)
+	}
+}
+
+func TestDesktopUsesDBus(t *testing.T) {
+	sessionBus(t)
+
+	// No server on the bus: notify-send
+	n, err := registry["desktop"](nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Name() != "desktop" {
+		t.Errorf("without a server got %s, want the notify-send fallback", n.Name())
+	}
+
+	newFakeServer(t)
+	n, err = registry["desktop"](nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, ok := n.(*dbusNotifier)
+	if !ok {
+		t.Fatalf("with a server got %s, want dbus", n.Name())
+	}
+	d.conn.Close()
+}
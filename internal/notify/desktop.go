@@ -1,15 +1,31 @@
 package notify
 
 import (
+	"encoding/json"
 	"os/exec"
 )
 
-func Desktop(title, message string) error {
-	cmd := exec.Command("notify-send", "-a", "gclaude", "-u", "normal", title, message)
-	return cmd.Run()
+// The desktop notifier uses the dbus notifier, for actions and replacement
+// per session, and notify-send when there's no session bus or notification
+// server to talk to directly.
+func init() {
+	Register("desktop", func(options json.RawMessage) (Notifier, error) {
+		if d, err := newDBus(dbusOptions{Actions: true, TimeoutMs: -1}); err == nil {
+			return d, nil
+		}
+		return &desktopNotifier{}, nil
+	})
 }
 
-func DesktopUrgent(title, message string) error {
-	cmd := exec.Command("notify-send", "-a", "gclaude", "-u", "critical", title, message)
+type desktopNotifier struct{}
+
+func (d *desktopNotifier) Name() string { return "desktop" }
+
+func (d *desktopNotifier) Notify(ev Event) error {
+	urgency := ev.Urgency
+	if urgency == "" {
+		urgency = UrgencyNormal
+	}
+	cmd := exec.Command("notify-send", "-a", "gclaude", "-u", string(urgency), ev.Title, ev.Body())
 	return cmd.Run()
 }
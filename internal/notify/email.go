@@ -0,0 +1,220 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+func init() {
+	Register("email", func(options json.RawMessage) (Notifier, error) {
+		opts := emailOptions{
+			Port:         587,
+			StartTLS:     true,
+			RateLimitMin: 30,
+			Subject:      defaultEmailSubject,
+			Body:         defaultEmailBody,
+			TimeoutS:     30,
+		}
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return newEmail(opts)
+	})
+}
+
+const (
+	defaultEmailSubject = `[gclaude] {{.Branch}}: {{.State}}`
+	defaultEmailBody    = `{{.Message}}
+{{if .Excerpt}}
+    {{.Excerpt}}
+{{end}}
+Branch:   {{.Branch}}
+Worktree: {{.WorktreePath}}
+Time:     {{.Time.Format "2006-01-02 15:04:05"}}
+
+Attach with: gclaude attach {{.Branch}}
+`
+)
+
+type emailOptions struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	// PasswordEnv names an environment variable holding the password
+	PasswordEnv string   `json:"password_env,omitempty"`
+	From        string   `json:"from"`
+	To          []string `json:"to"`
+	// StartTLS upgrades a plain connection; TLS connects with implicit TLS
+	// (usually port 465)
+	StartTLS bool `json:"starttls"`
+	TLS      bool `json:"tls,omitempty"`
+	// Subject and Body are text/templates over the event
+	Subject string `json:"subject,omitempty"`
+	Body    string `json:"body,omitempty"`
+	// RateLimitMin is the minimum time between mails about one session
+	RateLimitMin int `json:"rate_limit_min,omitempty"`
+	TimeoutS     int `json:"timeout_s,omitempty"`
+}
+
+type emailNotifier struct {
+	opts    emailOptions
+	subject *template.Template
+	body    *template.Template
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+func newEmail(opts emailOptions) (Notifier, error) {
+	if opts.Host == "" || opts.From == "" || len(opts.To) == 0 {
+		return nil, fmt.Errorf("email needs host, from and to")
+	}
+	if opts.Password == "" && opts.PasswordEnv != "" {
+		opts.Password = os.Getenv(opts.PasswordEnv)
+	}
+
+	subject, err := template.New("subject").Funcs(templateFuncs).Parse(opts.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email subject template: %w", err)
+	}
+	body, err := template.New("body").Funcs(templateFuncs).Parse(opts.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email body template: %w", err)
+	}
+
+	return &emailNotifier{
+		opts:     opts,
+		subject:  subject,
+		body:     body,
+		lastSent: make(map[string]time.Time),
+	}, nil
+}
+
+func (e *emailNotifier) Name() string { return "email" }
+
+func (e *emailNotifier) Notify(ev Event) error {
+	if !e.allow(ev) {
+		return nil
+	}
+
+	msg, err := e.message(ev)
+	if err == nil {
+		err = e.send(msg)
+	}
+	if err != nil {
+		// Don't let a failed attempt hold back the next one
+		e.mu.Lock()
+		delete(e.lastSent, ev.SessionID)
+		e.mu.Unlock()
+	}
+	return err
+}
+
+// allow applies the per-session rate limit
+func (e *emailNotifier) allow(ev Event) bool {
+	limit := time.Duration(e.opts.RateLimitMin) * time.Minute
+	now := time.Now()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if last, ok := e.lastSent[ev.SessionID]; ok && now.Sub(last) < limit {
+		return false
+	}
+	e.lastSent[ev.SessionID] = now
+	return true
+}
+
+func (e *emailNotifier) message(ev Event) ([]byte, error) {
+	var subject, body bytes.Buffer
+	if err := e.subject.Execute(&subject, ev); err != nil {
+		return nil, fmt.Errorf("failed to render email subject: %w", err)
+	}
+	if err := e.body.Execute(&body, ev); err != nil {
+		return nil, fmt.Errorf("failed to render email body: %w", err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", e.opts.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.opts.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", strings.TrimSpace(subject.String())))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+	return msg.Bytes(), nil
+}
+
+func (e *emailNotifier) send(msg []byte) error {
+	addr := net.JoinHostPort(e.opts.Host, strconv.Itoa(e.opts.Port))
+	timeout := time.Duration(e.opts.TimeoutS) * time.Second
+	tlsConfig := &tls.Config{ServerName: e.opts.Host}
+
+	var conn net.Conn
+	var err error
+	if e.opts.TLS {
+		conn, err = tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, timeout)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	c, err := smtp.NewClient(conn, e.opts.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if !e.opts.TLS && e.opts.StartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS", e.opts.Host)
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+
+	if e.opts.Username != "" {
+		auth := smtp.PlainAuth("", e.opts.Username, e.opts.Password, e.opts.Host)
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(e.opts.From); err != nil {
+		return err
+	}
+	for _, to := range e.opts.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpServer speaks just enough SMTP for net/smtp and collects the messages
+type smtpServer struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	messages []string
+}
+
+func newSMTPServer(t *testing.T) *smtpServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &smtpServer{ln: ln}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpServer) port() int {
+	return s.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.Fields(line + " x")[0])
+		switch cmd {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case "DATA":
+			reply("354 go ahead")
+			var msg strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				msg.WriteString(strings.TrimPrefix(line, "."))
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg.String())
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func (s *smtpServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.messages...)
+}
+
+func newTestEmail(t *testing.T, port int) Notifier {
+	t.Helper()
+	options, _ := json.Marshal(map[string]any{
+		"host":     "127.0.0.1",
+		"port":     port,
+		"from":     "gclaude@example.com",
+		"to":       []string{"dev@example.com"},
+		"starttls": false,
+		"subject":  "[gclaude] {{.Branch}} — {{.State}}",
+	})
+	n, err := registry["email"](options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestEmail(t *testing.T) {
+	server := newSMTPServer(t)
+	n := newTestEmail(t, server.port())
+
+	ev := testEvent
+	ev.Branch = "feat/größe"
+	if err := n.Notify(ev); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := server.received()
+	if len(messages) != 1 {
+		t.Fatalf("got %d mails, want 1", len(messages))
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := msg.Header.Get("Subject")
+	if !strings.HasPrefix(raw, "=?utf-8?q?") {
+		t.Errorf("Subject %q is not Q-encoded", raw)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[gclaude] feat/größe — waiting"; subject != want {
+		t.Errorf("Subject = %q, want %q", subject, want)
+	}
+	if got := msg.Header.Get("To"); got != "dev@example.com" {
+		t.Errorf("To = %q", got)
+	}
+
+	body, err := io.ReadAll(msg.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{testEvent.Message, testEvent.Excerpt, "Worktree: " + testEvent.WorktreePath, "gclaude attach feat/größe"} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestEmailRateLimitPerSession(t *testing.T) {
+	server := newSMTPServer(t)
+	n := newTestEmail(t, server.port())
+
+	first := testEvent
+	if err := n.Notify(first); err != nil {
+		t.Fatal(err)
+	}
+	again := first
+	again.State = StateFinished
+	if err := n.Notify(again); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(server.received()); got != 1 {
+		t.Fatalf("got %d mails after a second event for the session, want 1", got)
+	}
+
+	other := first
+	other.SessionID = "s2"
+	if err := n.Notify(other); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(server.received()); got != 2 {
+		t.Errorf("got %d mails, want another session to get through", got)
+	}
+}
@@ -1,95 +1,302 @@
 package notify
 
 import (
+	"context"
+	"encoding/json"
+	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/bb/gclaude/internal/procfs"
+	"github.com/bb/gclaude/internal/tmux"
+	"github.com/godbus/dbus/v5"
 )
 
-// IsTerminalFocused checks if the terminal containing a tmux session is the focused window
-func IsTerminalFocused(tty string) bool {
-	if tty == "" {
+// FocusDetector tells whether the user is looking at a tmux client, so
+// sessions in front of the user don't notify
+type FocusDetector interface {
+	Name() string
+	// Available reports whether the detector works in this environment
+	Available() bool
+	IsFocused(c tmux.Client) bool
+}
+
+var focusDetectors = map[string]FocusDetector{
+	"x11":      x11Focus{},
+	"sway":     swayFocus{},
+	"i3":       i3Focus{},
+	"hyprland": hyprlandFocus{},
+	"gnome":    gnomeFocus{},
+	"tmux":     tmuxFocus{},
+}
+
+// Checked in order by "auto"; tmux works everywhere, so it's last. x11 goes
+// before gnome so GNOME on Xorg uses xdotool rather than Shell.Eval.
+var focusAutoOrder = []string{"sway", "hyprland", "i3", "x11", "gnome", "tmux"}
+
+// NewFocusDetector returns the named detector, or picks one for the current
+// session when name is "" or "auto"
+func NewFocusDetector(name string) FocusDetector {
+	if d, ok := focusDetectors[name]; ok {
+		return d
+	}
+	for _, n := range focusAutoOrder {
+		if d := focusDetectors[n]; d.Available() {
+			return d
+		}
+	}
+	return tmuxFocus{}
+}
+
+// IsSessionFocused reports whether any client attached to tmuxSession is
+// focused according to d
+func IsSessionFocused(d FocusDetector, tmuxSession string) bool {
+	clients, err := tmux.ListClients(tmuxSession)
+	if err != nil {
 		return false
 	}
+	for _, c := range clients {
+		if c.Session == tmuxSession && d.IsFocused(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// windowOwnsClient reports whether the window process windowPID is the
+// terminal running the tmux client, i.e. one of the client's ancestors
+func windowOwnsClient(windowPID string, c tmux.Client) bool {
+	if windowPID == "" || windowPID == "0" {
+		return false
+	}
+	for _, pid := range getAncestors(c.PID) {
+		if pid == windowPID {
+			return true
+		}
+	}
+	return false
+}
+
+type x11Focus struct{}
+
+func (x11Focus) Name() string { return "x11" }
+
+func (x11Focus) Available() bool {
+	_, err := exec.LookPath("xdotool")
+	return err == nil && os.Getenv("DISPLAY") != "" && os.Getenv("WAYLAND_DISPLAY") == ""
+}
 
-	// Get the active window PID using xdotool
-	activeWinCmd := exec.Command("xdotool", "getactivewindow", "getwindowpid")
-	activeOut, err := activeWinCmd.Output()
+func (x11Focus) IsFocused(c tmux.Client) bool {
+	out, err := exec.Command("xdotool", "getactivewindow", "getwindowpid").Output()
 	if err != nil {
-		// xdotool not available or failed - can't determine focus
 		return false
 	}
-	activeWinPID := strings.TrimSpace(string(activeOut))
-	if activeWinPID == "" {
+	return windowOwnsClient(strings.TrimSpace(string(out)), c)
+}
+
+type swayFocus struct{}
+
+func (swayFocus) Name() string { return "sway" }
+
+func (swayFocus) Available() bool {
+	return os.Getenv("SWAYSOCK") != ""
+}
+
+func (swayFocus) IsFocused(c tmux.Client) bool {
+	out, err := exec.Command("swaymsg", "-t", "get_tree").Output()
+	if err != nil {
 		return false
 	}
 
-	// Find the process that owns the TTY (the terminal emulator)
-	// We look for the process group leader of the TTY
-	psCmd := exec.Command("ps", "-o", "pid=", "-t", strings.TrimPrefix(tty, "/dev/"))
-	psOut, err := psCmd.Output()
+	var root focusNode
+	if err := json.Unmarshal(out, &root); err != nil {
+		return false
+	}
+	if n := root.focused(); n != nil {
+		return windowOwnsClient(strconv.Itoa(n.PID), c)
+	}
+	return false
+}
+
+type i3Focus struct{}
+
+func (i3Focus) Name() string { return "i3" }
+
+func (i3Focus) Available() bool {
+	return os.Getenv("I3SOCK") != ""
+}
+
+// IsFocused finds the focused X window in i3's tree; i3 has no pids, so the
+// pid comes from xdotool
+func (i3Focus) IsFocused(c tmux.Client) bool {
+	out, err := exec.Command("i3-msg", "-t", "get_tree").Output()
 	if err != nil {
 		return false
 	}
 
-	// Get all PIDs using this TTY
-	pids := strings.Fields(string(psOut))
+	var root focusNode
+	if err := json.Unmarshal(out, &root); err != nil {
+		return false
+	}
+	n := root.focused()
+	if n == nil || n.Window == 0 {
+		return false
+	}
 
-	// For each PID, walk up to find if it matches the active window's process tree
-	for _, pid := range pids {
-		if isProcessInTree(pid, activeWinPID) {
-			return true
-		}
+	pid, err := exec.Command("xdotool", "getwindowpid", strconv.Itoa(n.Window)).Output()
+	if err != nil {
+		return false
 	}
+	return windowOwnsClient(strings.TrimSpace(string(pid)), c)
+}
 
-	// Also check if the active window's process tree contains any of our TTY processes
-	// by walking up from TTY processes to see if they share a common ancestor with active window
-	for _, pid := range pids {
-		if sharesTerminalAncestor(pid, activeWinPID) {
-			return true
+// focusNode is the subset of a sway/i3 tree node needed to find focus
+type focusNode struct {
+	Focused       bool        `json:"focused"`
+	PID           int         `json:"pid"`
+	Window        int         `json:"window"`
+	Nodes         []focusNode `json:"nodes"`
+	FloatingNodes []focusNode `json:"floating_nodes"`
+}
+
+func (n *focusNode) focused() *focusNode {
+	if n.Focused {
+		return n
+	}
+	for _, children := range [][]focusNode{n.Nodes, n.FloatingNodes} {
+		for i := range children {
+			if f := children[i].focused(); f != nil {
+				return f
+			}
 		}
 	}
+	return nil
+}
 
-	return false
+type hyprlandFocus struct{}
+
+func (hyprlandFocus) Name() string { return "hyprland" }
+
+func (hyprlandFocus) Available() bool {
+	return os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != ""
 }
 
-// isProcessInTree checks if pid is in the process tree rooted at rootPid
-func isProcessInTree(pid, rootPid string) bool {
-	current := pid
-	for i := 0; i < 20; i++ { // Max depth
-		if current == rootPid {
-			return true
-		}
-		if current == "" || current == "1" || current == "0" {
-			break
-		}
-		// Get parent PID
-		ppidCmd := exec.Command("ps", "-o", "ppid=", "-p", current)
-		ppidOut, err := ppidCmd.Output()
-		if err != nil {
-			break
-		}
-		current = strings.TrimSpace(string(ppidOut))
+func (hyprlandFocus) IsFocused(c tmux.Client) bool {
+	out, err := exec.Command("hyprctl", "activewindow", "-j").Output()
+	if err != nil {
+		return false
 	}
-	return false
+
+	var win struct {
+		PID int `json:"pid"`
+	}
+	if err := json.Unmarshal(out, &win); err != nil {
+		return false
+	}
+	return windowOwnsClient(strconv.Itoa(win.PID), c)
 }
 
-// sharesTerminalAncestor checks if two PIDs share a common terminal emulator ancestor
-func sharesTerminalAncestor(pid1, pid2 string) bool {
-	// Get ancestors of pid1
-	ancestors1 := getAncestors(pid1)
-	ancestors2 := getAncestors(pid2)
+type gnomeFocus struct{}
 
-	// Check for common ancestor (excluding init/systemd)
-	for _, a1 := range ancestors1 {
-		for _, a2 := range ancestors2 {
-			if a1 == a2 && a1 != "1" && a1 != "0" {
-				return true
-			}
+const gnomeFocusedPID = "global.display.focus_window ? String(global.display.focus_window.get_pid()) : ''"
+
+var (
+	gnomeEvalOnce sync.Once
+	gnomeEvalOK   bool
+)
+
+func (gnomeFocus) Name() string { return "gnome" }
+
+// Available checks once that Shell.Eval works. Since GNOME 41 it's only
+// allowed with unsafe mode or development builds.
+func (gnomeFocus) Available() bool {
+	if !strings.Contains(os.Getenv("XDG_CURRENT_DESKTOP"), "GNOME") {
+		return false
+	}
+	gnomeEvalOnce.Do(func() {
+		_, gnomeEvalOK = gnomeEval("0")
+	})
+	return gnomeEvalOK
+}
+
+// IsFocused asks GNOME Shell for the focused window's pid
+func (gnomeFocus) IsFocused(c tmux.Client) bool {
+	pid, ok := gnomeEval(gnomeFocusedPID)
+	if !ok {
+		return false
+	}
+	return windowOwnsClient(pid, c)
+}
+
+// gnomeEval runs script in GNOME Shell, returning its result and whether the
+// Shell ran it
+func gnomeEval(script string) (string, bool) {
+	conn, err := dbus.SessionBus()
+	if err != nil {
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbusCallTimeout)
+	defer cancel()
+
+	var ok bool
+	var result string
+	err = conn.Object("org.gnome.Shell", "/org/gnome/Shell").
+		CallWithContext(ctx, "org.gnome.Shell.Eval", 0, script).Store(&ok, &result)
+	if err != nil || !ok {
+		return "", false
+	}
+	// The result is JSON; String() makes it a quoted string
+	var s string
+	if json.Unmarshal([]byte(result), &s) == nil {
+		result = s
+	}
+	return result, true
+}
+
+// tmuxFocus works without a desktop: a client attached to the session counts
+// as focused when its terminal reports focus (needs 'set -g focus-events on')
+// and it shows the session's active pane, the one the monitor watches
+type tmuxFocus struct{}
+
+func (tmuxFocus) Name() string { return "tmux" }
+
+func (tmuxFocus) Available() bool { return true }
+
+func (tmuxFocus) IsFocused(c tmux.Client) bool {
+	out, err := exec.Command("tmux", "display-message", "-c", c.TTY, "-p",
+		"#{client_flags}\t#{client_session}\t#{window_active}#{pane_active}").Output()
+	if err != nil {
+		return false
+	}
+	return tmuxClientFocused(strings.TrimSpace(string(out)), c.Session)
+}
+
+// tmuxClientFocused parses tmuxFocus's display-message output
+func tmuxClientFocused(line, session string) bool {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 3 || fields[1] != session || fields[2] != "11" {
+		return false
+	}
+
+	var attached, focused bool
+	for _, flag := range strings.Split(fields[0], ",") {
+		switch flag {
+		case "attached":
+			attached = true
+		case "focused":
+			focused = true
+		case "suspended":
+			return false
 		}
 	}
-	return false
+	return attached && focused
 }
 
+// getAncestors returns pid and its ancestors up to (not including) init,
+// read from /proc
 func getAncestors(pid string) []string {
 	var ancestors []string
 	current := pid
@@ -98,12 +305,7 @@ func getAncestors(pid string) []string {
 			break
 		}
 		ancestors = append(ancestors, current)
-		ppidCmd := exec.Command("ps", "-o", "ppid=", "-p", current)
-		ppidOut, err := ppidCmd.Output()
-		if err != nil {
-			break
-		}
-		current = strings.TrimSpace(string(ppidOut))
+		current, _, _ = procfs.ReadStat(current)
 	}
 	return ancestors
 }
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+func TestTmuxClientFocused(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want bool
+	}{
+		{"focused on the agent pane", "attached,focused,UTF-8\tgclaude-feat\t11", true},
+		{"terminal not focused", "attached,UTF-8\tgclaude-feat\t11", false},
+		{"switched to another session", "attached,focused,UTF-8\tother\t11", false},
+		{"on another window", "attached,focused,UTF-8\tgclaude-feat\t01", false},
+		{"on another pane", "attached,focused,UTF-8\tgclaude-feat\t10", false},
+		{"suspended", "attached,focused,suspended\tgclaude-feat\t11", false},
+		{"garbage", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tmuxClientFocused(tt.line, "gclaude-feat"); got != tt.want {
+				t.Errorf("tmuxClientFocused(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeShell answers org.gnome.Shell.Eval like GNOME 41+ outside unsafe mode
+// when locked, and with a pid otherwise
+type fakeShell struct {
+	locked atomic.Bool
+}
+
+func (s *fakeShell) Eval(script string) (bool, string, *dbus.Error) {
+	if s.locked.Load() {
+		return false, "", nil
+	}
+	return true, `"4242"`, nil
+}
+
+func TestGnomeEval(t *testing.T) {
+	sessionBus(t)
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	shell := &fakeShell{}
+	shell.locked.Store(true)
+	conn.Export(shell, "/org/gnome/Shell", "org.gnome.Shell")
+	if _, err := conn.RequestName("org.gnome.Shell", dbus.NameFlagDoNotQueue); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := gnomeEval("0"); ok {
+		t.Error("gnomeEval succeeded with Eval disabled")
+	}
+
+	shell.locked.Store(false)
+	pid, ok := gnomeEval(gnomeFocusedPID)
+	if !ok || pid != "4242" {
+		t.Errorf("gnomeEval() = %q, %v, want 4242", pid, ok)
+	}
+}
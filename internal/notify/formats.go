@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+)
+
+// Preset payload builders for the webhook notifier, selected with "format"
+var payloadFormats = map[string]func(ev Event) ([]byte, error){
+	"json":    func(ev Event) ([]byte, error) { return json.Marshal(ev) },
+	"slack":   slackPayload,
+	"discord": discordPayload,
+	"matrix":  matrixPayload,
+}
+
+func attachCommand(ev Event) string {
+	return "gclaude attach " + ev.Branch
+}
+
+func repoName(ev Event) string {
+	if ev.RepoPath == "" {
+		return ""
+	}
+	return filepath.Base(ev.RepoPath)
+}
+
+func stateEmoji(s State) string {
+	switch s {
+	case StateWaiting:
+		return "⏳"
+	case StateFinished:
+		return "✅"
+	case StateError, StateRateLimited:
+		return "⚠️"
+	case StateExited:
+		return "⛔"
+	case StateStuck:
+		return "🐢"
+	}
+	return "🔔"
+}
+
+// codeFence wraps s in a Markdown code block, breaking up any fences inside it
+func codeFence(s string) string {
+	return "```\n" + strings.ReplaceAll(s, "```", "`​``") + "\n```"
+}
+
+func slackPayload(ev Event) ([]byte, error) {
+	header := fmt.Sprintf("%s %s: %s", stateEmoji(ev.State), ev.Branch, ev.State)
+
+	fields := []map[string]string{
+		{"type": "mrkdwn", "text": "*Branch*\n" + ev.Branch},
+		{"type": "mrkdwn", "text": "*State*\n" + string(ev.State)},
+	}
+	if repo := repoName(ev); repo != "" {
+		fields = append(fields, map[string]string{"type": "mrkdwn", "text": "*Repo*\n" + repo})
+	}
+
+	blocks := []map[string]any{
+		{"type": "header", "text": map[string]string{"type": "plain_text", "text": header}},
+		{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": ev.Message}, "fields": fields},
+	}
+	if ev.Excerpt != "" {
+		blocks = append(blocks, map[string]any{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": codeFence(ev.Excerpt)},
+		})
+	}
+	if !ev.IsSummary() {
+		blocks = append(blocks, map[string]any{
+			"type":     "context",
+			"elements": []map[string]string{{"type": "mrkdwn", "text": "`" + attachCommand(ev) + "`"}},
+		})
+	}
+
+	return json.Marshal(map[string]any{
+		"text":   header + " - " + ev.Message,
+		"blocks": blocks,
+	})
+}
+
+var discordColors = map[State]int{
+	StateWaiting:     0xF1C40F,
+	StateFinished:    0x2ECC71,
+	StateError:       0xE67E22,
+	StateRateLimited: 0xE67E22,
+	StateExited:      0xE74C3C,
+	StateStuck:       0x9B59B6,
+}
+
+func discordPayload(ev Event) ([]byte, error) {
+	description := ev.Message
+	if ev.Excerpt != "" {
+		description += "\n" + codeFence(ev.Excerpt)
+	}
+
+	fields := []map[string]any{
+		{"name": "Branch", "value": ev.Branch, "inline": true},
+		{"name": "State", "value": string(ev.State), "inline": true},
+	}
+	if repo := repoName(ev); repo != "" {
+		fields = append(fields, map[string]any{"name": "Repo", "value": repo, "inline": true})
+	}
+
+	embed := map[string]any{
+		"title":       fmt.Sprintf("%s %s", stateEmoji(ev.State), ev.Title),
+		"description": description,
+		"color":       discordColors[ev.State],
+		"fields":      fields,
+	}
+	if !ev.IsSummary() {
+		embed["footer"] = map[string]string{"text": attachCommand(ev)}
+	}
+	if !ev.Time.IsZero() {
+		embed["timestamp"] = ev.Time.Format("2006-01-02T15:04:05Z07:00")
+	}
+
+	return json.Marshal(map[string]any{
+		"username": "gclaude",
+		"embeds":   []any{embed},
+	})
+}
+
+func matrixPayload(ev Event) ([]byte, error) {
+	var plain strings.Builder
+	fmt.Fprintf(&plain, "%s %s: %s\n", stateEmoji(ev.State), ev.Branch, ev.Message)
+	if repo := repoName(ev); repo != "" {
+		fmt.Fprintf(&plain, "repo: %s, state: %s\n", repo, ev.State)
+	}
+	if ev.Excerpt != "" {
+		plain.WriteString(codeFence(ev.Excerpt) + "\n")
+	}
+	if !ev.IsSummary() {
+		plain.WriteString(attachCommand(ev))
+	}
+
+	var formatted strings.Builder
+	fmt.Fprintf(&formatted, "%s <b>%s</b>: %s<br>", stateEmoji(ev.State), html.EscapeString(ev.Branch), html.EscapeString(ev.Message))
+	if repo := repoName(ev); repo != "" {
+		fmt.Fprintf(&formatted, "repo: %s, state: %s<br>", html.EscapeString(repo), ev.State)
+	}
+	if ev.Excerpt != "" {
+		fmt.Fprintf(&formatted, "<pre><code>%s</code></pre>", html.EscapeString(ev.Excerpt))
+	}
+	if !ev.IsSummary() {
+		fmt.Fprintf(&formatted, "<code>%s</code>", html.EscapeString(attachCommand(ev)))
+	}
+
+	return json.Marshal(map[string]string{
+		"msgtype":        "m.notice",
+		"body":           plain.String(),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": formatted.String(),
+	})
+}
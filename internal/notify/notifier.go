@@ -0,0 +1,299 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bb/gclaude/internal/config"
+)
+
+// State is what happened to a session
+type State string
+
+const (
+	StateWaiting     State = "waiting"
+	StateFinished    State = "finished"
+	StateError       State = "error"
+	StateExited      State = "exited"
+	StateStuck       State = "stuck"
+	StateRateLimited State = "rate_limited"
+	// StateSummary lists events held back by quiet hours or digests
+	StateSummary State = "summary"
+)
+
+type Urgency string
+
+const (
+	UrgencyLow      Urgency = "low"
+	UrgencyNormal   Urgency = "normal"
+	UrgencyCritical Urgency = "critical"
+)
+
+// Event is a structured notification fanned out to every enabled notifier
+type Event struct {
+	Time         time.Time `json:"time"`
+	SessionID    string    `json:"session_id"`
+	Branch       string    `json:"branch"`
+	RepoPath     string    `json:"repo_path"`
+	WorktreePath string    `json:"worktree_path"`
+	TmuxSession  string    `json:"tmux_session"`
+	State        State     `json:"state"`
+	Urgency      Urgency   `json:"urgency"`
+	Priority     string    `json:"priority,omitempty"`
+	Title        string    `json:"title"`
+	Message      string    `json:"message"`
+	Excerpt      string    `json:"excerpt,omitempty"`
+}
+
+// Notifier delivers events through one backend
+type Notifier interface {
+	Name() string
+	Notify(ev Event) error
+}
+
+// Actions a user can trigger from an interactive notification
+const (
+	ActionAttach = "attach"
+	ActionSnooze = "snooze"
+	ActionMute   = "mute"
+)
+
+// ActionHandler is called when the user clicks an action on a notification
+type ActionHandler func(ev Event, action string)
+
+// Interactive is implemented by notifiers that can report clicked actions
+type Interactive interface {
+	SetActionHandler(h ActionHandler)
+}
+
+// Factory builds a notifier from its raw JSON options
+type Factory func(options json.RawMessage) (Notifier, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Factory)
+)
+
+// Register makes a notifier type available to the config
+func Register(name string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = f
+}
+
+// Types returns the registered notifier types
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func decodeOptions(options json.RawMessage, v any) error {
+	if len(options) == 0 {
+		return nil
+	}
+	return json.Unmarshal(options, v)
+}
+
+// queueSize bounds the events waiting for one notifier. Beyond it events for
+// that notifier are dropped rather than blocking the monitor.
+const queueSize = 32
+
+type entry struct {
+	typ      string
+	notifier Notifier
+	events   map[State]bool
+	queue    chan Event
+}
+
+// ErrorHandler is called with the failures of asynchronous deliveries
+type ErrorHandler func(ev Event, err error)
+
+// Dispatcher fans events out to all configured notifiers. Each notifier has
+// its own queue and goroutine, so a slow backend (a webhook retrying, SMTP,
+// a sound playing) delays only its own events.
+type Dispatcher struct {
+	entries []*entry
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	onError ErrorHandler
+}
+
+// Error is a failure of a single notifier
+type Error struct {
+	Notifier string
+	Err      error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Notifier, e.Err)
+}
+
+// NewDispatcher builds the notifiers listed in cfg. Notifiers that fail to
+// build are skipped and returned as errors.
+func NewDispatcher(cfg config.NotificationConfig) (*Dispatcher, []error) {
+	d := &Dispatcher{}
+	var errs []error
+
+	for _, nc := range EnabledNotifiers(cfg) {
+		registryMu.RLock()
+		factory, ok := registry[nc.Type]
+		registryMu.RUnlock()
+		if !ok {
+			errs = append(errs, &Error{Notifier: nc.Type, Err: fmt.Errorf("unknown notifier type")})
+			continue
+		}
+
+		n, err := factory(nc.Options)
+		if err != nil {
+			errs = append(errs, &Error{Notifier: nc.Type, Err: err})
+			continue
+		}
+
+		e := &entry{typ: nc.Type, notifier: n, queue: make(chan Event, queueSize)}
+		if len(nc.Events) > 0 {
+			e.events = make(map[State]bool)
+			for _, s := range nc.Events {
+				e.events[State(s)] = true
+			}
+		}
+		d.entries = append(d.entries, e)
+
+		d.wg.Add(1)
+		go d.deliver(e)
+	}
+
+	return d, errs
+}
+
+// deliver sends the events queued for one notifier, in order
+func (d *Dispatcher) deliver(e *entry) {
+	defer d.wg.Done()
+	for ev := range e.queue {
+		if err := e.notifier.Notify(ev); err != nil {
+			d.reportError(ev, &Error{Notifier: e.notifier.Name(), Err: err})
+		}
+	}
+}
+
+// SetErrorHandler registers h to be told about failed deliveries
+func (d *Dispatcher) SetErrorHandler(h ErrorHandler) {
+	d.mu.Lock()
+	d.onError = h
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) reportError(ev Event, err error) {
+	d.mu.Lock()
+	h := d.onError
+	d.mu.Unlock()
+	if h != nil {
+		h(ev, err)
+	}
+}
+
+// EnabledNotifiers returns the notifiers listed in the config, falling back
+// to the desktop and sound switches when none are listed.
+func EnabledNotifiers(cfg config.NotificationConfig) []config.NotifierConfig {
+	if len(cfg.Notifiers) > 0 {
+		return cfg.Notifiers
+	}
+
+	var list []config.NotifierConfig
+	if cfg.Desktop {
+		list = append(list, config.NotifierConfig{Type: "desktop"})
+	}
+	if cfg.Sound {
+		opts, _ := json.Marshal(soundOptions{
+			File:   cfg.SoundFile,
+			Files:  cfg.SoundFiles,
+			Volume: cfg.SoundVolume,
+			Player: cfg.SoundPlayer,
+		})
+		list = append(list, config.NotifierConfig{Type: "sound", Options: opts})
+	}
+	return list
+}
+
+// SetActionHandler registers h with every notifier that supports actions
+func (d *Dispatcher) SetActionHandler(h ActionHandler) {
+	for _, e := range d.entries {
+		if in, ok := e.notifier.(Interactive); ok {
+			in.SetActionHandler(h)
+		}
+	}
+}
+
+// Send queues ev for every notifier subscribed to its state and returns
+// without waiting for delivery. Failures go to the error handler.
+func (d *Dispatcher) Send(ev Event) {
+	d.SendTo(ev, nil)
+}
+
+// SendTo is Send limited to the notifier types in allow (all if nil)
+func (d *Dispatcher) SendTo(ev Event, allow []string) {
+	for _, e := range d.entries {
+		if e.events != nil && !e.events[ev.State] {
+			continue
+		}
+		if allow != nil && !contains(allow, e.typ) {
+			continue
+		}
+		select {
+		case e.queue <- ev:
+		default:
+			d.reportError(ev, &Error{Notifier: e.typ, Err: fmt.Errorf("queue full, notification dropped")})
+		}
+	}
+}
+
+// Close stops accepting events and waits up to timeout for the queued ones
+// to be delivered
+func (d *Dispatcher) Close(timeout time.Duration) {
+	for _, e := range d.entries {
+		close(e.queue)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Body is the message followed by the excerpt, for notifiers that show plain text
+func (ev Event) Body() string {
+	if ev.Excerpt == "" {
+		return ev.Message
+	}
+	return ev.Message + "\n" + ev.Excerpt
+}
+
+// IsSummary reports whether ev covers several sessions (a digest or the
+// quiet-hours summary) rather than one, so it has nothing to attach to
+func (ev Event) IsSummary() bool {
+	return ev.SessionID == ""
+}
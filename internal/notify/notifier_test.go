@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bb/gclaude/internal/config"
+)
+
+// testNotifier records events, optionally blocking until released
+type testNotifier struct {
+	name    string
+	release chan struct{}
+	err     error
+
+	mu     sync.Mutex
+	events []Event
+}
+
+func (n *testNotifier) Name() string { return n.name }
+
+func (n *testNotifier) Notify(ev Event) error {
+	if n.release != nil {
+		<-n.release
+	}
+	n.mu.Lock()
+	n.events = append(n.events, ev)
+	n.mu.Unlock()
+	return n.err
+}
+
+func registerTest(n *testNotifier) {
+	Register(n.name, func(json.RawMessage) (Notifier, error) { return n, nil })
+}
+
+func TestDispatcherDoesNotWaitForSlowNotifiers(t *testing.T) {
+	slow := &testNotifier{name: "test-slow", release: make(chan struct{})}
+	failing := &testNotifier{name: "test-failing", err: errors.New("boom")}
+	registerTest(slow)
+	registerTest(failing)
+
+	d, errs := NewDispatcher(config.NotificationConfig{Notifiers: []config.NotifierConfig{
+		{Type: "test-slow"},
+		{Type: "test-failing", Events: []string{"waiting"}},
+	}})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	reported := make(chan error, 4)
+	d.SetErrorHandler(func(ev Event, err error) { reported <- err })
+
+	sent := make(chan struct{})
+	go func() {
+		d.Send(Event{SessionID: "a", State: StateWaiting})
+		d.Send(Event{SessionID: "a", State: StateFinished})
+		close(sent)
+	}()
+	select {
+	case <-sent:
+	case <-time.After(time.Second):
+		t.Fatal("Send blocked on a slow notifier")
+	}
+
+	select {
+	case err := <-reported:
+		var nerr *Error
+		if !errors.As(err, &nerr) || nerr.Notifier != "test-failing" {
+			t.Errorf("reported %v, want an error from test-failing", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("failure was not reported")
+	}
+
+	close(slow.release)
+	d.Close(time.Second)
+
+	if len(slow.events) != 2 {
+		t.Errorf("slow notifier got %d events, want 2", len(slow.events))
+	}
+	// Subscribed to waiting only
+	if len(failing.events) != 1 {
+		t.Errorf("failing notifier got %d events, want 1", len(failing.events))
+	}
+}
+
+func TestDispatcherDropsWhenQueueIsFull(t *testing.T) {
+	slow := &testNotifier{name: "test-full", release: make(chan struct{})}
+	registerTest(slow)
+
+	d, _ := NewDispatcher(config.NotificationConfig{Notifiers: []config.NotifierConfig{{Type: "test-full"}}})
+	var mu sync.Mutex
+	dropped := 0
+	d.SetErrorHandler(func(ev Event, err error) {
+		mu.Lock()
+		dropped++
+		mu.Unlock()
+	})
+
+	// One event is being delivered, queueSize wait, the rest are dropped
+	for i := 0; i < queueSize+5; i++ {
+		d.Send(Event{State: StateFinished})
+	}
+	close(slow.release)
+	d.Close(time.Second)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := len(slow.events) + dropped; got != queueSize+5 {
+		t.Errorf("delivered %d + dropped %d, want %d in total", len(slow.events), dropped, queueSize+5)
+	}
+	if dropped < 4 {
+		t.Errorf("dropped %d, want at least 4", dropped)
+	}
+}
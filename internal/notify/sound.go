@@ -1,48 +1,141 @@
 package notify
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 )
 
-var defaultSoundPaths = []string{
-	"/usr/share/sounds/freedesktop/stereo/complete.oga",
-	"/usr/share/sounds/freedesktop/stereo/message.oga",
-	"/usr/share/sounds/freedesktop/stereo/bell.oga",
-	"/usr/share/sounds/gnome/default/alerts/drip.ogg",
-	"/usr/share/sounds/ubuntu/stereo/message.ogg",
+func init() {
+	Register("sound", func(options json.RawMessage) (Notifier, error) {
+		opts := soundOptions{Volume: 100}
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		if opts.Volume < 0 || opts.Volume > 100 {
+			return nil, fmt.Errorf("sound volume must be between 0 and 100")
+		}
+		return &soundNotifier{opts: opts}, nil
+	})
 }
 
-func Sound(customPath string) error {
-	soundFile := customPath
-	if soundFile == "" {
-		soundFile = findDefaultSound()
+type soundOptions struct {
+	// File is played for every event without an entry in Files
+	File string `json:"file,omitempty"`
+	// Files maps permission, finished, error and stuck to sound files
+	Files  map[string]string `json:"files,omitempty"`
+	Volume int               `json:"volume"`
+	// Player is a command the built-in sounds are passed to as a WAV file
+	Player string `json:"player,omitempty"`
+}
+
+type soundNotifier struct {
+	opts soundOptions
+}
+
+func (s *soundNotifier) Name() string { return "sound" }
+
+func (s *soundNotifier) Notify(ev Event) error {
+	if s.opts.Volume == 0 {
+		return nil
+	}
+	return s.play(soundEvent(ev.State))
+}
+
+// soundEvent maps a session state to the sound theme entry played for it
+func soundEvent(state State) string {
+	switch state {
+	case StateWaiting:
+		return "permission"
+	case StateError, StateExited, StateRateLimited:
+		return "error"
+	case StateStuck:
+		return "stuck"
+	default:
+		return "finished"
 	}
+}
 
-	if soundFile == "" {
-		return playBeep()
+func (s *soundNotifier) play(event string) error {
+	file := s.opts.Files[event]
+	if file == "" {
+		file = s.opts.File
 	}
 
-	return playFile(soundFile)
+	// Compressed files need a player; WAV files and the built-in theme can
+	// be decoded here
+	if file != "" && !strings.EqualFold(filepath.Ext(file), ".wav") {
+		return playFile(file, s.opts.Volume)
+	}
+
+	var clip *pcm
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if clip, err = decodeWAV(data); err != nil {
+			return playFile(file, s.opts.Volume)
+		}
+	} else {
+		clip = builtinSound(event)
+	}
+	return playPCM(clip.scaled(s.opts.Volume), s.opts.Player)
 }
 
-func findDefaultSound() string {
-	for _, path := range defaultSoundPaths {
-		if _, err := os.Stat(path); err == nil {
-			return path
+// Sound plays customPath, or the built-in sound when it's empty
+func Sound(customPath string) error {
+	s := &soundNotifier{opts: soundOptions{File: customPath, Volume: 100}}
+	return s.play("finished")
+}
+
+// playPCM hands clip to player, or to the first available player, or writes
+// it to the sound card directly
+func playPCM(clip *pcm, player string) error {
+	if player == "" {
+		for _, p := range []string{"paplay", "pw-play", "aplay -q"} {
+			if _, err := exec.LookPath(strings.Fields(p)[0]); err == nil {
+				player = p
+				break
+			}
 		}
 	}
-	return ""
+	if player == "" {
+		if err := playALSA(clip); err == nil {
+			return nil
+		}
+		return playBeep()
+	}
+
+	f, err := os.CreateTemp("", "gclaude-*.wav")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(clip.wav())
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	args := strings.Fields(player)
+	return exec.Command(args[0], append(args[1:], f.Name())...).Run()
 }
 
-func playFile(path string) error {
+func playFile(path string, volume int) error {
 	ext := filepath.Ext(path)
 
+	// paplay takes the volume as 0..65536
+	paplay := []string{"--volume=" + strconv.Itoa(volume*65536/100), path}
+
 	switch ext {
 	case ".ogg", ".oga":
 		if _, err := exec.LookPath("paplay"); err == nil {
-			return exec.Command("paplay", path).Run()
+			return exec.Command("paplay", paplay...).Run()
 		}
 		if _, err := exec.LookPath("ogg123"); err == nil {
 			return exec.Command("ogg123", "-q", path).Run()
@@ -52,16 +145,16 @@ func playFile(path string) error {
 			return exec.Command("aplay", "-q", path).Run()
 		}
 		if _, err := exec.LookPath("paplay"); err == nil {
-			return exec.Command("paplay", path).Run()
+			return exec.Command("paplay", paplay...).Run()
 		}
 	case ".mp3":
 		if _, err := exec.LookPath("mpg123"); err == nil {
-			return exec.Command("mpg123", "-q", path).Run()
+			return exec.Command("mpg123", "-q", "-f", strconv.Itoa(volume*32768/100), path).Run()
 		}
 	}
 
 	if _, err := exec.LookPath("paplay"); err == nil {
-		return exec.Command("paplay", path).Run()
+		return exec.Command("paplay", paplay...).Run()
 	}
 	if _, err := exec.LookPath("aplay"); err == nil {
 		return exec.Command("aplay", "-q", path).Run()
@@ -70,14 +163,8 @@ func playFile(path string) error {
 	return playBeep()
 }
 
+// playBeep is the last resort. It only reaches a terminal when gclaude runs
+// in the foreground.
 func playBeep() error {
-	if _, err := exec.LookPath("paplay"); err == nil {
-		for _, path := range defaultSoundPaths {
-			if _, err := os.Stat(path); err == nil {
-				return exec.Command("paplay", path).Run()
-			}
-		}
-	}
-
 	return exec.Command("printf", "\a").Run()
 }
@@ -0,0 +1,182 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// pcm is mono 16-bit audio
+type pcm struct {
+	rate    int
+	samples []int16
+}
+
+const builtinRate = 48000
+
+// tone is a note of the built-in theme; a zero frequency is a rest
+type tone struct {
+	freq float64
+	ms   int
+}
+
+// The built-in theme is synthesized, so it needs no sound files
+var builtinTheme = map[string][]tone{
+	// Two rising notes, asking for attention
+	"permission": {{880, 110}, {0, 40}, {1175, 180}},
+	// Rising major arpeggio
+	"finished": {{523.25, 100}, {659.25, 100}, {783.99, 240}},
+	// Falling minor third, lower and longer
+	"error": {{392, 180}, {0, 30}, {311.13, 280}},
+	// Three short repeated beeps
+	"stuck": {{660, 90}, {0, 70}, {660, 90}, {0, 70}, {660, 90}},
+}
+
+func builtinSound(event string) *pcm {
+	tones, ok := builtinTheme[event]
+	if !ok {
+		tones = builtinTheme["finished"]
+	}
+	return synthesize(tones, builtinRate)
+}
+
+// synthesize renders tones as a sine with a soft second harmonic, each with a
+// short attack and an exponential decay so notes don't click
+func synthesize(tones []tone, rate int) *pcm {
+	p := &pcm{rate: rate}
+	attack := float64(rate) * 0.005
+
+	for _, t := range tones {
+		n := rate * t.ms / 1000
+		for i := 0; i < n; i++ {
+			if t.freq == 0 {
+				p.samples = append(p.samples, 0)
+				continue
+			}
+			x := 2 * math.Pi * t.freq * float64(i) / float64(rate)
+			v := math.Sin(x) + 0.25*math.Sin(2*x)
+
+			env := math.Exp(-3 * float64(i) / float64(n))
+			if fi := float64(i); fi < attack {
+				env *= fi / attack
+			}
+			p.samples = append(p.samples, int16(v*env*0.4*math.MaxInt16))
+		}
+	}
+	return p
+}
+
+// scaled returns p with its amplitude scaled to volume percent
+func (p *pcm) scaled(volume int) *pcm {
+	if volume >= 100 {
+		return p
+	}
+	out := &pcm{rate: p.rate, samples: make([]int16, len(p.samples))}
+	for i, s := range p.samples {
+		out.samples[i] = int16(int(s) * volume / 100)
+	}
+	return out
+}
+
+// resampled converts p to rate with linear interpolation
+func (p *pcm) resampled(rate int) *pcm {
+	if rate == p.rate || len(p.samples) == 0 {
+		return p
+	}
+	n := int(int64(len(p.samples)) * int64(rate) / int64(p.rate))
+	out := &pcm{rate: rate, samples: make([]int16, n)}
+	step := float64(p.rate) / float64(rate)
+	for i := range out.samples {
+		pos := float64(i) * step
+		j := int(pos)
+		if j+1 >= len(p.samples) {
+			out.samples[i] = p.samples[len(p.samples)-1]
+			continue
+		}
+		frac := pos - float64(j)
+		out.samples[i] = int16(float64(p.samples[j])*(1-frac) + float64(p.samples[j+1])*frac)
+	}
+	return out
+}
+
+// frames interleaves p into little-endian frames of the given channel count
+func (p *pcm) frames(channels int) []byte {
+	buf := make([]byte, 0, len(p.samples)*2*channels)
+	for _, s := range p.samples {
+		for c := 0; c < channels; c++ {
+			buf = binary.LittleEndian.AppendUint16(buf, uint16(s))
+		}
+	}
+	return buf
+}
+
+// wav encodes p as a RIFF WAVE file
+func (p *pcm) wav() []byte {
+	data := p.frames(1)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	binary.Write(&buf, binary.LittleEndian, uint32(36+len(data)))
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16),         // fmt chunk size
+		uint16(1),          // PCM
+		uint16(1),          // channels
+		uint32(p.rate),     // sample rate
+		uint32(p.rate * 2), // byte rate
+		uint16(2),          // block align
+		uint16(16),         // bits per sample
+	} {
+		binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	binary.Write(&buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// decodeWAV reads 16-bit PCM WAV files, mixing stereo down to mono
+func decodeWAV(data []byte) (*pcm, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, fmt.Errorf("not a WAV file")
+	}
+
+	var (
+		format, channels, bits uint16
+		rate                   uint32
+	)
+	for off := 12; off+8 <= len(data); {
+		id := string(data[off : off+4])
+		size := int(binary.LittleEndian.Uint32(data[off+4 : off+8]))
+		body := data[off+8 : min(off+8+size, len(data))]
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, fmt.Errorf("invalid WAV format chunk")
+			}
+			format = binary.LittleEndian.Uint16(body[0:2])
+			channels = binary.LittleEndian.Uint16(body[2:4])
+			rate = binary.LittleEndian.Uint32(body[4:8])
+			bits = binary.LittleEndian.Uint16(body[14:16])
+		case "data":
+			if format != 1 || bits != 16 || channels == 0 || rate == 0 {
+				return nil, fmt.Errorf("unsupported WAV encoding, want 16-bit PCM")
+			}
+			frame := 2 * int(channels)
+			p := &pcm{rate: int(rate), samples: make([]int16, len(body)/frame)}
+			for i := range p.samples {
+				sum := 0
+				for c := 0; c < int(channels); c++ {
+					sum += int(int16(binary.LittleEndian.Uint16(body[i*frame+2*c:])))
+				}
+				p.samples[i] = int16(sum / int(channels))
+			}
+			return p, nil
+		}
+		// Chunks are padded to an even size
+		off += 8 + size + size%2
+	}
+	return nil, fmt.Errorf("WAV file has no data")
+}
@@ -0,0 +1,230 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/bb/gclaude/internal/tmux"
+)
+
+// Launch prefixes for known terminals; the attach command is appended
+var terminalPresets = map[string][]string{
+	"kitty":          {"kitty"},
+	"wezterm":        {"wezterm", "start", "--"},
+	"alacritty":      {"alacritty", "-e"},
+	"gnome-terminal": {"gnome-terminal", "--"},
+	"konsole":        {"konsole", "-e"},
+	"foot":           {"foot"},
+	"xterm":          {"xterm", "-e"},
+}
+
+// Tried in order when no terminal is configured
+var terminalFallbacks = []string{"kitty", "wezterm", "alacritty", "foot", "gnome-terminal", "konsole", "xterm"}
+
+// OpenSession brings the user to a session: it focuses a terminal window
+// already attached to the tmux session if one can be found, and otherwise
+// starts terminal (e.g. "kitty", "alacritty -e") running 'gclaude attach'.
+func OpenSession(terminal, branch, tmuxSession string) error {
+	if FocusAttachedTerminal(tmuxSession) {
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	prefix, err := terminalCommand(terminal)
+	if err != nil {
+		return err
+	}
+
+	args := append(append([]string{}, prefix[1:]...), exe, "attach", branch)
+	cmd := exec.Command(prefix[0], args...)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
+func terminalCommand(terminal string) ([]string, error) {
+	if terminal != "" {
+		if preset, ok := terminalPresets[terminal]; ok {
+			return preset, nil
+		}
+		return strings.Fields(terminal), nil
+	}
+
+	for _, name := range terminalFallbacks {
+		if _, err := exec.LookPath(name); err == nil {
+			return terminalPresets[name], nil
+		}
+	}
+	if _, err := exec.LookPath("x-terminal-emulator"); err == nil {
+		return []string{"x-terminal-emulator", "-e"}, nil
+	}
+	return nil, fmt.Errorf("no terminal emulator found, set notification.terminal")
+}
+
+// FocusAttachedTerminal tries to raise a terminal window showing tmuxSession.
+// It returns false if nothing is attached or no supported method worked.
+func FocusAttachedTerminal(tmuxSession string) bool {
+	clients, err := tmux.ListClients(tmuxSession)
+	if err != nil || len(clients) == 0 {
+		return false
+	}
+
+	for _, c := range clients {
+		if focusKitty(c) || focusWezterm(c) || focusSway(c) || focusI3(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// focusKitty uses kitty remote control (allow_remote_control must be on)
+func focusKitty(c tmux.Client) bool {
+	if os.Getenv("KITTY_LISTEN_ON") == "" {
+		if _, err := exec.LookPath("kitty"); err != nil {
+			return false
+		}
+	}
+
+	out, err := exec.Command("kitty", "@", "ls").Output()
+	if err != nil {
+		return false
+	}
+
+	var osWindows []struct {
+		Tabs []struct {
+			Windows []struct {
+				ID                  int `json:"id"`
+				ForegroundProcesses []struct {
+					PID int `json:"pid"`
+				} `json:"foreground_processes"`
+			} `json:"windows"`
+		} `json:"tabs"`
+	}
+	if err := json.Unmarshal(out, &osWindows); err != nil {
+		return false
+	}
+
+	for _, ow := range osWindows {
+		for _, tab := range ow.Tabs {
+			for _, w := range tab.Windows {
+				for _, p := range w.ForegroundProcesses {
+					if strconv.Itoa(p.PID) == c.PID {
+						return exec.Command("kitty", "@", "focus-window", "--match", fmt.Sprintf("id:%d", w.ID)).Run() == nil
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// focusWezterm matches the client's tty against wezterm's panes
+func focusWezterm(c tmux.Client) bool {
+	if _, err := exec.LookPath("wezterm"); err != nil {
+		return false
+	}
+
+	out, err := exec.Command("wezterm", "cli", "list", "--format", "json").Output()
+	if err != nil {
+		return false
+	}
+
+	var panes []struct {
+		PaneID  int    `json:"pane_id"`
+		TTYName string `json:"tty_name"`
+	}
+	if err := json.Unmarshal(out, &panes); err != nil {
+		return false
+	}
+
+	for _, p := range panes {
+		if p.TTYName == c.TTY {
+			return exec.Command("wezterm", "cli", "activate-pane", "--pane-id", strconv.Itoa(p.PaneID)).Run() == nil
+		}
+	}
+	return false
+}
+
+// focusSway finds the window whose process is an ancestor of the client
+func focusSway(c tmux.Client) bool {
+	if os.Getenv("SWAYSOCK") == "" {
+		return false
+	}
+
+	out, err := exec.Command("swaymsg", "-t", "get_tree").Output()
+	if err != nil {
+		return false
+	}
+
+	var root swayNode
+	if err := json.Unmarshal(out, &root); err != nil {
+		return false
+	}
+
+	ancestors := make(map[int]bool)
+	for _, a := range getAncestors(c.PID) {
+		if pid, err := strconv.Atoi(a); err == nil {
+			ancestors[pid] = true
+		}
+	}
+
+	if id, ok := root.find(ancestors); ok {
+		return exec.Command("swaymsg", fmt.Sprintf("[con_id=%d]", id), "focus").Run() == nil
+	}
+	return false
+}
+
+type swayNode struct {
+	ID            int        `json:"id"`
+	PID           int        `json:"pid"`
+	Nodes         []swayNode `json:"nodes"`
+	FloatingNodes []swayNode `json:"floating_nodes"`
+}
+
+func (n *swayNode) find(pids map[int]bool) (int, bool) {
+	if n.PID != 0 && pids[n.PID] {
+		return n.ID, true
+	}
+	for _, children := range [][]swayNode{n.Nodes, n.FloatingNodes} {
+		for i := range children {
+			if id, ok := children[i].find(pids); ok {
+				return id, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// focusI3 looks up the X window of the client's terminal with xdotool, as
+// i3's tree has no pids, and focuses it through i3-msg
+func focusI3(c tmux.Client) bool {
+	if os.Getenv("I3SOCK") == "" {
+		if err := exec.Command("i3-msg", "-t", "get_version").Run(); err != nil {
+			return false
+		}
+	}
+
+	for _, pid := range getAncestors(c.PID) {
+		out, err := exec.Command("xdotool", "search", "--pid", pid).Output()
+		if err != nil {
+			continue
+		}
+		for _, win := range strings.Fields(string(bytes.TrimSpace(out))) {
+			if exec.Command("i3-msg", fmt.Sprintf("[id=%s]", win), "focus").Run() == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bb/gclaude/internal/tmux"
+)
+
+// The tmux notifier alerts through the terminal itself, for sessions used
+// over SSH where there's no desktop to notify.
+
+func init() {
+	Register("tmux", func(options json.RawMessage) (Notifier, error) {
+		opts := tmuxOptions{
+			DisplayMessage: true,
+			Bell:           true,
+			OSC:            "9",
+			DurationMs:     5000,
+		}
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		if !oscModes[opts.OSC] {
+			return nil, fmt.Errorf("unknown tmux osc: %s", opts.OSC)
+		}
+		return &tmuxNotifier{opts: opts}, nil
+	})
+}
+
+type tmuxOptions struct {
+	// DisplayMessage shows the notification in every client's status line
+	DisplayMessage bool `json:"display_message"`
+	// Popup opens a popup listing the sessions waiting for input
+	Popup bool `json:"popup"`
+	// Bell rings the terminal bell
+	Bell bool `json:"bell"`
+	// OSC picks the escape that terminals turn into native notifications:
+	// "9" (iTerm2, WezTerm, Ghostty), "777" (urxvt, foot, WezTerm, Ghostty),
+	// "both" or "off". Terminals that know only one may print the other.
+	OSC        string `json:"osc"`
+	DurationMs int    `json:"duration_ms"`
+}
+
+var oscModes = map[string]bool{"9": true, "777": true, "both": true, "off": true}
+
+type tmuxNotifier struct {
+	opts tmuxOptions
+}
+
+func (t *tmuxNotifier) Name() string { return "tmux" }
+
+func (t *tmuxNotifier) Notify(ev Event) error {
+	clients, err := tmux.ListClients("")
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, c := range clients {
+		if t.opts.DisplayMessage {
+			msg := fmt.Sprintf("%s: %s", ev.Title, firstLine(ev.Message))
+			if ev.Excerpt != "" {
+				msg += " - " + firstLine(ev.Excerpt)
+			}
+			if err := tmux.DisplayMessage(c.TTY, msg, time.Duration(t.opts.DurationMs)*time.Millisecond); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if t.opts.Bell || t.opts.OSC != "off" {
+			if err := t.writeTTY(c.TTY, ev); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if t.opts.Popup && (ev.State == StateWaiting || ev.State == StateSummary) {
+			if err := t.popup(c.TTY); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// writeTTY writes the bell and OSC escapes straight to the client's terminal,
+// bypassing tmux, so they reach the outer terminal (also over SSH).
+func (t *tmuxNotifier) writeTTY(tty string, ev Event) error {
+	f, err := os.OpenFile(tty, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.WriteString(t.escapes(ev))
+	return err
+}
+
+// escapes returns the OSC notification and bell to write for ev
+func (t *tmuxNotifier) escapes(ev Event) string {
+	var b strings.Builder
+	if t.opts.OSC != "off" {
+		title := oscSafe(ev.Title)
+		body := firstLine(ev.Message)
+		if ev.Excerpt != "" {
+			body += " - " + firstLine(ev.Excerpt)
+		}
+		body = oscSafe(body)
+		if t.opts.OSC == "777" || t.opts.OSC == "both" {
+			fmt.Fprintf(&b, "\x1b]777;notify;%s;%s\x07", title, body)
+		}
+		if t.opts.OSC == "9" || t.opts.OSC == "both" {
+			fmt.Fprintf(&b, "\x1b]9;%s: %s\x07", title, body)
+		}
+	}
+	if t.opts.Bell {
+		b.WriteString("\a")
+	}
+	return b.String()
+}
+
+func (t *tmuxNotifier) popup(tty string) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	command := fmt.Sprintf("%q list --waiting; printf '\\nPress Enter to close'; read _", exe)
+	return tmux.DisplayPopup(tty, " gclaude: waiting sessions ", command)
+}
+
+// oscSafe strips characters that would end or corrupt an OSC sequence
+func oscSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return ' '
+		}
+		return r
+	}, s)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
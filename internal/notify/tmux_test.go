@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTmuxOSC(t *testing.T) {
+	const (
+		osc9   = "\x1b]9;"
+		osc777 = "\x1b]777;notify;"
+	)
+	tests := []struct {
+		options string
+		osc9    int
+		osc777  int
+	}{
+		{``, 1, 0},
+		{`{"osc": "9"}`, 1, 0},
+		{`{"osc": "777"}`, 0, 1},
+		{`{"osc": "both"}`, 1, 1},
+		{`{"osc": "off"}`, 0, 0},
+	}
+	for _, tt := range tests {
+		n, err := registry["tmux"]([]byte(tt.options))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.options, err)
+		}
+		out := n.(*tmuxNotifier).escapes(testEvent)
+		if got := strings.Count(out, osc9); got != tt.osc9 {
+			t.Errorf("%s: %d OSC 9 sequences in %q", tt.options, got, out)
+		}
+		if got := strings.Count(out, osc777); got != tt.osc777 {
+			t.Errorf("%s: %d OSC 777 sequences in %q", tt.options, got, out)
+		}
+		if !strings.HasSuffix(out, "\a") {
+			t.Errorf("%s: no bell in %q", tt.options, out)
+		}
+	}
+
+	if _, err := registry["tmux"]([]byte(`{"osc": "99"}`)); err == nil {
+		t.Error("unknown osc accepted")
+	}
+}
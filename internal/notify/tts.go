@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// The tts notifier speaks events through a local text-to-speech engine, so a
+// user with headphones on hears which session needs attention.
+
+func init() {
+	Register("tts", func(options json.RawMessage) (Notifier, error) {
+		opts := ttsOptions{Template: defaultTTSTemplate}
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return newTTS(opts)
+	})
+}
+
+const defaultTTSTemplate = `{{spoken .Branch}}: {{.Message}}`
+
+// Engines tried by "auto", in order; piper needs a model so it comes last
+var ttsEngines = []string{"espeak-ng", "spd-say", "espeak", "piper"}
+
+type ttsOptions struct {
+	// Engine is espeak-ng, espeak, spd-say, piper or auto (default)
+	Engine string `json:"engine,omitempty"`
+	// Template is a text/template over the event, e.g.
+	// "{{.Branch}} needs permission to run tests"
+	Template string `json:"template,omitempty"`
+	Voice    string `json:"voice,omitempty"`
+	// Rate is in words per minute, 0 for the engine's default
+	Rate int `json:"rate,omitempty"`
+	// Model is the .onnx voice used by piper
+	Model string `json:"model,omitempty"`
+	// Player plays piper's output, like the sound notifier's player
+	Player string `json:"player,omitempty"`
+}
+
+type ttsNotifier struct {
+	opts   ttsOptions
+	engine string
+	tmpl   *template.Template
+	// Announcements are spoken one at a time
+	mu sync.Mutex
+}
+
+var ttsFuncs = template.FuncMap{
+	// spoken turns "feature/login-page" into "feature login page"
+	"spoken": func(s string) string {
+		return strings.NewReplacer("/", " ", "-", " ", "_", " ", ".", " ").Replace(s)
+	},
+}
+
+func newTTS(opts ttsOptions) (Notifier, error) {
+	engine := opts.Engine
+	if engine == "" || engine == "auto" {
+		engine = ""
+		for _, e := range ttsEngines {
+			if e == "piper" && opts.Model == "" {
+				continue
+			}
+			if _, err := exec.LookPath(e); err == nil {
+				engine = e
+				break
+			}
+		}
+		if engine == "" {
+			return nil, fmt.Errorf("no text-to-speech engine found (tried %s)", strings.Join(ttsEngines, ", "))
+		}
+	} else if _, err := exec.LookPath(engine); err != nil {
+		return nil, fmt.Errorf("%s not found: %w", engine, err)
+	}
+	if engine == "piper" && opts.Model == "" {
+		return nil, fmt.Errorf("piper needs a voice model")
+	}
+
+	tmpl, err := template.New("tts").Funcs(templateFuncs).Funcs(ttsFuncs).Parse(opts.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tts template: %w", err)
+	}
+	return &ttsNotifier{opts: opts, engine: engine, tmpl: tmpl}, nil
+}
+
+func (t *ttsNotifier) Name() string { return "tts" }
+
+func (t *ttsNotifier) Notify(ev Event) error {
+	var buf bytes.Buffer
+	if err := t.tmpl.Execute(&buf, ev); err != nil {
+		return fmt.Errorf("failed to render tts template: %w", err)
+	}
+	text := strings.Join(strings.Fields(buf.String()), " ")
+	if text == "" {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.speak(text)
+}
+
+func (t *ttsNotifier) speak(text string) error {
+	var args []string
+	switch t.engine {
+	case "espeak-ng", "espeak":
+		if t.opts.Voice != "" {
+			args = append(args, "-v", t.opts.Voice)
+		}
+		if t.opts.Rate > 0 {
+			args = append(args, "-s", strconv.Itoa(t.opts.Rate))
+		}
+	case "spd-say":
+		// Wait, so announcements don't cut each other off
+		args = append(args, "-w")
+		if t.opts.Voice != "" {
+			args = append(args, "-y", t.opts.Voice)
+		}
+	case "piper":
+		return t.speakPiper(text)
+	}
+
+	out, err := exec.Command(t.engine, append(args, "--", text)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s: %s", t.engine, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// speakPiper renders text to a WAV file and plays it like a sound
+func (t *ttsNotifier) speakPiper(text string) error {
+	f, err := os.CreateTemp("", "gclaude-tts-*.wav")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	args := []string{"--model", t.opts.Model, "--output_file", f.Name()}
+	if t.opts.Voice != "" {
+		args = append(args, "--speaker", t.opts.Voice)
+	}
+	if t.opts.Rate > 0 {
+		// piper's length scale is relative to its natural ~175 words/min
+		args = append(args, "--length_scale", strconv.FormatFloat(175/float64(t.opts.Rate), 'f', 2, 64))
+	}
+
+	cmd := exec.Command("piper", args...)
+	cmd.Stdin = strings.NewReader(text)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("piper: %s", strings.TrimSpace(string(out)))
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return err
+	}
+	clip, err := decodeWAV(data)
+	if err != nil {
+		return err
+	}
+	return playPCM(clip, t.opts.Player)
+}
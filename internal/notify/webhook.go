@@ -0,0 +1,185 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+)
+
+func init() {
+	Register("webhook", func(options json.RawMessage) (Notifier, error) {
+		opts := webhookOptions{
+			TimeoutS:  10,
+			Retries:   3,
+			BackoffMs: 500,
+		}
+		if err := decodeOptions(options, &opts); err != nil {
+			return nil, err
+		}
+		return newWebhook(opts)
+	})
+}
+
+type webhookOptions struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Secret signs the body with HMAC-SHA256 in the X-Gclaude-Signature header
+	Secret string `json:"secret,omitempty"`
+	// Format picks a preset body: json (default), slack, discord or matrix
+	Format string `json:"format,omitempty"`
+	// Template is a text/template for the body, overriding Format
+	Template  string `json:"template,omitempty"`
+	TimeoutS  int    `json:"timeout_s,omitempty"`
+	Retries   int    `json:"retries,omitempty"`
+	BackoffMs int    `json:"backoff_ms,omitempty"`
+}
+
+type webhookNotifier struct {
+	opts   webhookOptions
+	tmpl   *template.Template
+	format func(ev Event) ([]byte, error)
+	client *http.Client
+}
+
+var templateFuncs = template.FuncMap{
+	// json renders a value as a JSON literal, for embedding strings safely
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func newWebhook(opts webhookOptions) (Notifier, error) {
+	if opts.URL == "" {
+		return nil, fmt.Errorf("webhook url is required")
+	}
+
+	w := &webhookNotifier{
+		opts:   opts,
+		client: &http.Client{Timeout: time.Duration(opts.TimeoutS) * time.Second},
+	}
+
+	if opts.Format == "" {
+		opts.Format = "json"
+	}
+	format, ok := payloadFormats[opts.Format]
+	if !ok {
+		return nil, fmt.Errorf("unknown webhook format: %s", opts.Format)
+	}
+	if opts.Method == "" {
+		opts.Method = http.MethodPost
+		// Matrix sends room messages with PUT to .../send/m.room.message/{txnId}
+		if opts.Format == "matrix" {
+			opts.Method = http.MethodPut
+		}
+	}
+	w.opts = opts
+	w.format = format
+
+	if opts.Template != "" {
+		tmpl, err := template.New("webhook").Funcs(templateFuncs).Parse(opts.Template)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook template: %w", err)
+		}
+		w.tmpl = tmpl
+	}
+
+	return w, nil
+}
+
+func (w *webhookNotifier) Name() string { return "webhook" }
+
+func (w *webhookNotifier) Notify(ev Event) error {
+	body, err := w.render(ev)
+	if err != nil {
+		return err
+	}
+
+	url := w.opts.URL
+	if w.opts.Format == "matrix" && strings.HasSuffix(url, "/send/m.room.message") {
+		// Same transaction id on every retry so Matrix deduplicates them
+		url += fmt.Sprintf("/gclaude-%d", time.Now().UnixNano())
+	}
+
+	backoff := time.Duration(w.opts.BackoffMs) * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		err = w.post(url, body)
+		if err == nil || attempt >= w.opts.Retries {
+			return err
+		}
+		if perm, ok := err.(*permanentError); ok {
+			return perm.err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (w *webhookNotifier) render(ev Event) ([]byte, error) {
+	if w.tmpl == nil {
+		return w.format(ev)
+	}
+
+	var buf bytes.Buffer
+	if err := w.tmpl.Execute(&buf, ev); err != nil {
+		return nil, fmt.Errorf("failed to render webhook template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// permanentError is a response that retrying won't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (w *webhookNotifier) post(url string, body []byte) error {
+	req, err := http.NewRequest(w.opts.Method, url, bytes.NewReader(body))
+	if err != nil {
+		return &permanentError{err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gclaude")
+	for k, v := range w.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	if w.opts.Secret != "" {
+		req.Header.Set("X-Gclaude-Signature", "sha256="+Sign(w.opts.Secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		err := fmt.Errorf("webhook returned %s", resp.Status)
+		// Client errors other than rate limiting won't succeed on retry
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			return &permanentError{err}
+		}
+		return err
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body with secret, as sent in the
+// X-Gclaude-Signature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,302 @@
+package notify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// request is what the test server saw
+type request struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+// webhookServer answers with the given status codes in turn, then 200
+func webhookServer(t *testing.T, statuses ...int) (*httptest.Server, func() []request) {
+	t.Helper()
+	var (
+		mu   sync.Mutex
+		reqs []request
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		n := len(reqs)
+		reqs = append(reqs, request{Method: r.Method, Path: r.URL.Path, Header: r.Header.Clone(), Body: body})
+		mu.Unlock()
+
+		if n < len(statuses) {
+			w.WriteHeader(statuses[n])
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []request {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]request{}, reqs...)
+	}
+}
+
+func newTestWebhook(t *testing.T, opts webhookOptions) Notifier {
+	t.Helper()
+	if opts.TimeoutS == 0 {
+		opts.TimeoutS = 5
+	}
+	if opts.BackoffMs == 0 {
+		opts.BackoffMs = 1
+	}
+	n, err := newWebhook(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+var testEvent = Event{
+	Time:         time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+	SessionID:    "s1",
+	Branch:       "feat/login",
+	RepoPath:     "/src/shop",
+	WorktreePath: "/src/shop-worktrees/feat-login",
+	State:        StateWaiting,
+	Urgency:      UrgencyNormal,
+	Title:        "gclaude: feat/login",
+	Message:      `Claude needs "permission"`,
+	Excerpt:      "Bash command: npm test <unit>",
+}
+
+func TestWebhookSignsBody(t *testing.T) {
+	srv, requests := webhookServer(t)
+	n := newTestWebhook(t, webhookOptions{URL: srv.URL, Secret: "s3cret"})
+
+	if err := n.Notify(testEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(reqs[0].Body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := reqs[0].Header.Get("X-Gclaude-Signature"); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+
+	var ev Event
+	if err := json.Unmarshal(reqs[0].Body, &ev); err != nil {
+		t.Fatalf("default body is not JSON: %v", err)
+	}
+	if ev.Branch != testEvent.Branch || ev.State != testEvent.State {
+		t.Errorf("body = %+v, want the event", ev)
+	}
+}
+
+func TestWebhookTemplate(t *testing.T) {
+	srv, requests := webhookServer(t)
+	n := newTestWebhook(t, webhookOptions{
+		URL:      srv.URL,
+		Template: `{"text": {{json .Message}}, "branch": {{json .Branch}}, "excerpt": {{json .Excerpt}}}`,
+	})
+
+	if err := n.Notify(testEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(requests()[0].Body, &body); err != nil {
+		t.Fatalf("template output is not JSON: %v", err)
+	}
+	if body["text"] != testEvent.Message || body["branch"] != testEvent.Branch || body["excerpt"] != testEvent.Excerpt {
+		t.Errorf("body = %v", body)
+	}
+}
+
+func TestWebhookRetriesServerErrors(t *testing.T) {
+	srv, requests := webhookServer(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+	n := newTestWebhook(t, webhookOptions{URL: srv.URL, Retries: 3})
+
+	if err := n.Notify(testEvent); err != nil {
+		t.Fatalf("Notify() = %v, want success after retries", err)
+	}
+	if got := len(requests()); got != 3 {
+		t.Errorf("got %d requests, want 3", got)
+	}
+}
+
+func TestWebhookGivesUpAfterRetries(t *testing.T) {
+	srv, requests := webhookServer(t, 500, 500, 500, 500, 500)
+	n := newTestWebhook(t, webhookOptions{URL: srv.URL, Retries: 2})
+
+	if err := n.Notify(testEvent); err == nil {
+		t.Fatal("Notify() succeeded, want an error")
+	}
+	if got := len(requests()); got != 3 {
+		t.Errorf("got %d requests, want 3", got)
+	}
+}
+
+func TestWebhookDoesNotRetryClientErrors(t *testing.T) {
+	srv, requests := webhookServer(t, http.StatusBadRequest)
+	n := newTestWebhook(t, webhookOptions{URL: srv.URL, Retries: 3})
+
+	if err := n.Notify(testEvent); err == nil {
+		t.Fatal("Notify() succeeded, want an error")
+	}
+	if got := len(requests()); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+}
+
+func TestWebhookRetriesTooManyRequests(t *testing.T) {
+	srv, requests := webhookServer(t, http.StatusTooManyRequests)
+	n := newTestWebhook(t, webhookOptions{URL: srv.URL, Retries: 1})
+
+	if err := n.Notify(testEvent); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(requests()); got != 2 {
+		t.Errorf("got %d requests, want 2", got)
+	}
+}
+
+func TestWebhookSlack(t *testing.T) {
+	srv, requests := webhookServer(t)
+	n := newTestWebhook(t, webhookOptions{URL: srv.URL, Format: "slack"})
+	if err := n.Notify(testEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	var body struct {
+		Text   string           `json:"text"`
+		Blocks []map[string]any `json:"blocks"`
+	}
+	if err := json.Unmarshal(requests()[0].Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body.Text, "feat/login") || !strings.Contains(body.Text, testEvent.Message) {
+		t.Errorf("text = %q", body.Text)
+	}
+	if len(body.Blocks) != 4 {
+		t.Fatalf("got %d blocks, want header, message, excerpt and context", len(body.Blocks))
+	}
+	if body.Blocks[0]["type"] != "header" || body.Blocks[3]["type"] != "context" {
+		t.Errorf("blocks = %v", body.Blocks)
+	}
+	excerpt := body.Blocks[2]["text"].(map[string]any)["text"].(string)
+	if !strings.HasPrefix(excerpt, "```") || !strings.Contains(excerpt, testEvent.Excerpt) {
+		t.Errorf("excerpt block = %q", excerpt)
+	}
+}
+
+func TestWebhookDiscord(t *testing.T) {
+	srv, requests := webhookServer(t)
+	n := newTestWebhook(t, webhookOptions{URL: srv.URL, Format: "discord"})
+	if err := n.Notify(testEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	var body struct {
+		Username string `json:"username"`
+		Embeds   []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Color       int    `json:"color"`
+			Timestamp   string `json:"timestamp"`
+			Fields      []struct {
+				Name  string `json:"name"`
+				Value string `json:"value"`
+			} `json:"fields"`
+			Footer struct {
+				Text string `json:"text"`
+			} `json:"footer"`
+		} `json:"embeds"`
+	}
+	if err := json.Unmarshal(requests()[0].Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Username != "gclaude" || len(body.Embeds) != 1 {
+		t.Fatalf("body = %+v", body)
+	}
+	e := body.Embeds[0]
+	if !strings.Contains(e.Title, testEvent.Title) || e.Color != discordColors[StateWaiting] {
+		t.Errorf("embed = %+v", e)
+	}
+	if e.Timestamp != "2026-10-18T12:00:00Z" {
+		t.Errorf("timestamp = %q", e.Timestamp)
+	}
+	if len(e.Fields) != 3 || e.Fields[2].Value != "shop" {
+		t.Errorf("fields = %+v, want branch, state and repo", e.Fields)
+	}
+	if e.Footer.Text != "gclaude attach feat/login" {
+		t.Errorf("footer = %q", e.Footer.Text)
+	}
+}
+
+func TestWebhookMatrix(t *testing.T) {
+	srv, requests := webhookServer(t, http.StatusBadGateway)
+	n := newTestWebhook(t, webhookOptions{
+		URL:     srv.URL + "/_matrix/client/v3/rooms/!room/send/m.room.message",
+		Format:  "matrix",
+		Retries: 1,
+	})
+	if err := n.Notify(testEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	for _, r := range reqs {
+		if r.Method != http.MethodPut {
+			t.Errorf("method = %s, want PUT", r.Method)
+		}
+	}
+	if !strings.Contains(reqs[0].Path, "/send/m.room.message/gclaude-") {
+		t.Errorf("path = %q, want a transaction id", reqs[0].Path)
+	}
+	if reqs[0].Path != reqs[1].Path {
+		t.Errorf("retry used transaction %q, first attempt %q", reqs[1].Path, reqs[0].Path)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(reqs[0].Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["msgtype"] != "m.notice" || body["format"] != "org.matrix.custom.html" {
+		t.Errorf("body = %v", body)
+	}
+	if !strings.Contains(body["formatted_body"], "&lt;unit&gt;") || strings.Contains(body["formatted_body"], "<unit>") {
+		t.Errorf("formatted_body not escaped: %q", body["formatted_body"])
+	}
+	if !strings.Contains(body["body"], "gclaude attach feat/login") {
+		t.Errorf("body = %q", body["body"])
+	}
+}
+
+func TestWebhookPresetsOmitAttachForSummaries(t *testing.T) {
+	summary := Event{State: StateFinished, Title: "gclaude: 3 notifications", Message: "feat: finished\nfix: waiting"}
+	for _, format := range []string{"slack", "discord", "matrix"} {
+		body, err := payloadFormats[format](summary)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(body), "gclaude attach") {
+			t.Errorf("%s payload for a summary has an attach command: %s", format, body)
+		}
+	}
+}
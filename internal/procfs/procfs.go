@@ -0,0 +1,32 @@
+// Package procfs reads process information from /proc
+package procfs
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReadStat returns the parent pid and utime+stime clock ticks of a process.
+func ReadStat(pid string) (ppid string, ticks uint64, ok bool) {
+	data, err := os.ReadFile(filepath.Join("/proc", pid, "stat"))
+	if err != nil {
+		return "", 0, false
+	}
+
+	// The command name is in parentheses and may contain spaces
+	s := string(data)
+	end := strings.LastIndexByte(s, ')')
+	if end < 0 {
+		return "", 0, false
+	}
+	fields := strings.Fields(s[end+1:])
+	// fields[0] is state, [1] ppid, [11] utime, [12] stime
+	if len(fields) < 13 {
+		return "", 0, false
+	}
+	utime, _ := strconv.ParseUint(fields[11], 10, 64)
+	stime, _ := strconv.ParseUint(fields[12], 10, 64)
+	return fields[1], utime + stime, true
+}
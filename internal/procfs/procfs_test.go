@@ -0,0 +1,25 @@
+package procfs
+
+import (
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestReadStat(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("no procfs")
+	}
+
+	ppid, _, ok := ReadStat(strconv.Itoa(os.Getpid()))
+	if !ok {
+		t.Fatal("ReadStat() of the test process failed")
+	}
+	if ppid != strconv.Itoa(os.Getppid()) {
+		t.Errorf("ppid = %s, want %d", ppid, os.Getppid())
+	}
+
+	if _, _, ok := ReadStat("0"); ok {
+		t.Error("ReadStat() of a missing process succeeded")
+	}
+}
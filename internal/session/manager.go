@@ -2,11 +2,15 @@ package session
 
 import (
 	"fmt"
+	"path/filepath"
+	"time"
 
 	"github.com/bb/gclaude/internal/tmux"
 	"github.com/bb/gclaude/internal/worktree"
 )
 
+const resumeCommand = "claude --continue"
+
 type Manager struct {
 	store *Store
 }
@@ -34,8 +38,8 @@ func (m *Manager) Start(branch, repoPath string, createWorktree bool) (*Session,
 	var sessionPath string
 
 	if createWorktree {
-		if worktree.Exists(repoRoot, branch) {
-			sessionPath = worktree.GetWorktreePath(repoRoot, branch)
+		if path := worktree.Find(repoRoot, branch); path != "" {
+			sessionPath = path
 		} else {
 			sessionPath, err = worktree.Create(repoRoot, branch)
 			if err != nil {
@@ -73,7 +77,7 @@ func (m *Manager) Stop(branch string, removeWorktree bool) error {
 	}
 
 	if removeWorktree && sess.WorktreePath != sess.RepoPath {
-		worktree.Remove(sess.RepoPath, sess.Branch)
+		worktree.Remove(sess.RepoPath, sess.WorktreePath)
 	}
 
 	return m.store.Remove(sess.ID)
@@ -100,13 +104,156 @@ func (m *Manager) Attach(branch string) error {
 
 	exists, _ := tmux.SessionExists(sess.TmuxSession)
 	if !exists {
-		m.store.Remove(sess.ID)
-		return fmt.Errorf("tmux session no longer exists")
+		return fmt.Errorf("tmux session no longer exists (see 'gclaude doctor')")
 	}
 
 	return tmux.AttachSession(sess.TmuxSession)
 }
 
+// Resume restarts the agent for a session whose process exited, continuing
+// the most recent conversation in its worktree.
+func (m *Manager) Resume(branch string) error {
+	sess := m.store.FindByBranch(branch)
+	if sess == nil {
+		return fmt.Errorf("no session found for branch '%s'", branch)
+	}
+
+	exists, _ := tmux.SessionExists(sess.TmuxSession)
+	if exists {
+		// respawn-pane -k would kill an agent that's still working
+		panes, err := tmux.ListPanes()
+		if err != nil {
+			return fmt.Errorf("failed to check the agent's pane: %w", err)
+		}
+		pane := panes[sess.TmuxSession]
+		dead := pane != nil && pane.Dead
+		if !dead && sess.Status != StatusExited && sess.Status != StatusStopped {
+			return fmt.Errorf("session '%s' is running, attach instead ('gclaude attach %s')", branch, branch)
+		}
+		if err := tmux.RespawnPane(sess.TmuxSession, sess.WorktreePath, resumeCommand); err != nil {
+			return fmt.Errorf("failed to respawn agent: %w", err)
+		}
+	} else {
+		if err := tmux.CreateSession(sess.TmuxSession, sess.WorktreePath, resumeCommand); err != nil {
+			return fmt.Errorf("failed to create tmux session: %w", err)
+		}
+	}
+
+	return m.store.Modify(sess.ID, func(s *Session) {
+		s.Status = StatusRunning
+		s.NeedsInput = false
+		s.ExitCode = 0
+		s.UpdateActivity()
+	})
+}
+
+// Mute silences notifications for a session, until the given time or
+// indefinitely when until is zero.
+func (m *Manager) Mute(branch string, until time.Time) error {
+	sess := m.store.FindByBranch(branch)
+	if sess == nil {
+		return fmt.Errorf("no session found for branch '%s'", branch)
+	}
+	return m.store.Modify(sess.ID, func(s *Session) {
+		s.Muted = until.IsZero()
+		s.MutedUntil = until
+	})
+}
+
+func (m *Manager) Unmute(branch string) error {
+	sess := m.store.FindByBranch(branch)
+	if sess == nil {
+		return fmt.Errorf("no session found for branch '%s'", branch)
+	}
+	return m.store.Modify(sess.ID, func(s *Session) {
+		s.Muted = false
+		s.MutedUntil = time.Time{}
+	})
+}
+
+func (m *Manager) SetPriority(branch string, priority Priority) error {
+	sess := m.store.FindByBranch(branch)
+	if sess == nil {
+		return fmt.Errorf("no session found for branch '%s'", branch)
+	}
+	return m.store.Modify(sess.ID, func(s *Session) {
+		s.Priority = priority
+	})
+}
+
+// Relocation is a worktree that RelocateWorktrees moved, or failed to move
+type Relocation struct {
+	Branch string
+	From   string
+	To     string
+	Err    error
+}
+
+// RelocateWorktrees moves the linked worktrees of the repository at repoPath
+// (only branch's, unless branch is empty) to where the configured strategy
+// puts them, and updates their sessions. Worktrees of running sessions are
+// skipped, since Claude's working directory would disappear under it.
+func (m *Manager) RelocateWorktrees(repoPath, branch string, dryRun bool) ([]Relocation, error) {
+	worktrees, err := worktree.List(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if len(worktrees) == 0 {
+		return nil, nil
+	}
+	// Locations are relative to the main worktree, even when run from a
+	// linked one
+	repoRoot := worktrees[0].Path
+
+	var moves []Relocation
+	for _, wt := range worktrees[1:] {
+		if wt.Branch == "" || (branch != "" && wt.Branch != branch) {
+			continue
+		}
+
+		target, err := worktree.GetWorktreePath(repoRoot, wt.Branch)
+		if err != nil {
+			return moves, err
+		}
+		if filepath.Clean(target) == filepath.Clean(wt.Path) {
+			continue
+		}
+
+		mv := Relocation{Branch: wt.Branch, From: wt.Path, To: target}
+		var sess *Session
+		for _, s := range m.store.GetAll() {
+			if filepath.Clean(s.WorktreePath) == filepath.Clean(wt.Path) {
+				sess = s
+				break
+			}
+		}
+
+		switch {
+		case sess != nil && tmuxRunning(sess.TmuxSession):
+			mv.Err = fmt.Errorf("session is running, stop it first")
+		case dryRun:
+		default:
+			mv.Err = worktree.Move(repoRoot, wt.Path, target)
+			if mv.Err == nil && sess != nil {
+				mv.Err = m.store.Modify(sess.ID, func(s *Session) {
+					s.WorktreePath = target
+				})
+			}
+		}
+		moves = append(moves, mv)
+	}
+
+	if branch != "" && len(moves) == 0 && worktree.Find(repoRoot, branch) == "" {
+		return nil, fmt.Errorf("no worktree found for branch '%s'", branch)
+	}
+	return moves, nil
+}
+
+func tmuxRunning(name string) bool {
+	exists, _ := tmux.SessionExists(name)
+	return exists
+}
+
 func (m *Manager) List() []*Session {
 	sessions := m.store.GetAll()
 
@@ -0,0 +1,69 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeTmux puts a tmux on PATH whose session's pane is dead or alive, and
+// which records respawn-pane calls in the returned file
+func fakeTmux(t *testing.T, paneDead string) string {
+	t.Helper()
+	dir := t.TempDir()
+	respawned := filepath.Join(dir, "respawned")
+	script := `#!/bin/sh
+case "$1" in
+has-session) exit 0 ;;
+list-panes) printf 'gclaude-feat\t11\t0\t` + paneDead + `\t0\t4242\tclaude\n' ;;
+respawn-pane) touch ` + respawned + ` ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "tmux"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return respawned
+}
+
+func TestResume(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   Status
+		paneDead string
+		respawn  bool
+	}{
+		{"agent working", StatusRunning, "0", false},
+		{"agent waiting", StatusWaitingInput, "0", false},
+		{"agent exited", StatusExited, "0", true},
+		{"pane dead before the monitor noticed", StatusRunning, "1", true},
+		{"marked stopped", StatusStopped, "0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			respawned := fakeTmux(t, tt.paneDead)
+			store := openStore(t)
+			if err := store.Clear(); err != nil {
+				t.Fatal(err)
+			}
+			store.Add(&Session{ID: "a", Branch: "feat", TmuxSession: "gclaude-feat", Status: tt.status})
+
+			err := (&Manager{store: store}).Resume("feat")
+			_, statErr := os.Stat(respawned)
+			if got := statErr == nil; got != tt.respawn {
+				t.Fatalf("respawned = %v, want %v (Resume() = %v)", got, tt.respawn, err)
+			}
+			if tt.respawn {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if s := store.FindByID("a"); s.Status != StatusRunning {
+					t.Errorf("status = %s, want running", s.Status)
+				}
+			} else if err == nil {
+				t.Error("Resume() of a live agent succeeded")
+			}
+		})
+	}
+}
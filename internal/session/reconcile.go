@@ -0,0 +1,69 @@
+package session
+
+import (
+	"os"
+	"strings"
+
+	"github.com/bb/gclaude/internal/tmux"
+)
+
+// ReconcileReport lists the differences between the store and tmux
+type ReconcileReport struct {
+	// Store entries whose tmux session is gone
+	Stale []*Session
+	// Entries in Stale that were running until this reconcile
+	MarkedStopped []*Session
+	// gclaude-* tmux sessions with no store entry
+	Orphans []string
+	// Store entries whose worktree directory was deleted
+	MissingWorktrees []*Session
+}
+
+func (r *ReconcileReport) Empty() bool {
+	return len(r.Stale) == 0 && len(r.Orphans) == 0 && len(r.MissingWorktrees) == 0
+}
+
+// Reconcile compares the store against the running tmux sessions and the
+// filesystem. Entries whose tmux session is gone are marked stopped; nothing
+// is deleted.
+func Reconcile(store *Store) (*ReconcileReport, error) {
+	names, err := tmux.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	running := make(map[string]bool, len(names))
+	for _, name := range names {
+		running[name] = true
+	}
+
+	report := &ReconcileReport{}
+	known := make(map[string]bool)
+
+	for _, sess := range store.GetAll() {
+		known[sess.TmuxSession] = true
+
+		if !running[sess.TmuxSession] {
+			report.Stale = append(report.Stale, sess)
+			if sess.Status != StatusStopped {
+				store.Modify(sess.ID, func(s *Session) {
+					s.Status = StatusStopped
+					s.NeedsInput = false
+				})
+				report.MarkedStopped = append(report.MarkedStopped, sess)
+			}
+		}
+
+		if info, err := os.Stat(sess.WorktreePath); err != nil || !info.IsDir() {
+			report.MissingWorktrees = append(report.MissingWorktrees, sess)
+		}
+	}
+
+	for _, name := range names {
+		if strings.HasPrefix(name, "gclaude-") && !known[name] {
+			report.Orphans = append(report.Orphans, name)
+		}
+	}
+
+	return report, nil
+}
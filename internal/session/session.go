@@ -1,6 +1,7 @@
 package session
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,16 +14,43 @@ const (
 	StatusWaitingInput Status = "waiting_input"
 	StatusIdle         Status = "idle"
 	StatusStopped      Status = "stopped"
+	StatusExited       Status = "exited"
+	StatusStuck        Status = "stuck"
+	StatusRateLimited  Status = "rate_limited"
 )
 
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+)
+
+func ParsePriority(s string) (Priority, error) {
+	switch p := Priority(s); p {
+	case PriorityLow, PriorityNormal, PriorityHigh:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid priority %q (want low, normal or high)", s)
+}
+
 type Session struct {
-	ID           string    `json:"id"`
-	Branch       string    `json:"branch"`
-	RepoPath     string    `json:"repo_path"`
-	WorktreePath string    `json:"worktree_path"`
-	TmuxSession  string    `json:"tmux_session"`
-	Status       Status    `json:"status"`
-	NeedsInput   bool      `json:"needs_input"`
+	ID           string `json:"id"`
+	Branch       string `json:"branch"`
+	RepoPath     string `json:"repo_path"`
+	WorktreePath string `json:"worktree_path"`
+	TmuxSession  string `json:"tmux_session"`
+	Status       Status `json:"status"`
+	NeedsInput   bool   `json:"needs_input"`
+	ExitCode     int    `json:"exit_code,omitempty"`
+	// Set while Status is StatusRateLimited
+	RateLimitedUntil time.Time `json:"rate_limited_until,omitempty"`
+	ContinueAttempts int       `json:"continue_attempts,omitempty"`
+	// Notifications are suppressed while Muted or until MutedUntil
+	Muted        bool      `json:"muted,omitempty"`
+	MutedUntil   time.Time `json:"muted_until,omitempty"`
+	Priority     Priority  `json:"priority,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
 	LastActivity time.Time `json:"last_activity"`
 	LastOutput   string    `json:"-"`
@@ -60,6 +88,10 @@ func (s *Session) UpdateActivity() {
 	s.LastActivity = time.Now()
 }
 
+func (s *Session) IsMuted(now time.Time) bool {
+	return s.Muted || now.Before(s.MutedUntil)
+}
+
 func (s *Session) SetNeedsInput(needs bool) {
 	s.NeedsInput = needs
 	if needs {
@@ -2,17 +2,21 @@ package session
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/bb/gclaude/internal/config"
+	"github.com/bb/gclaude/internal/filelock"
 )
 
 type Store struct {
 	mu       sync.RWMutex
 	Sessions []*Session `json:"sessions"`
 	filePath string
+	modTime  time.Time
 }
 
 var (
@@ -34,51 +38,137 @@ func GetStore() *Store {
 func (s *Store) load() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.read()
+}
 
-	data, err := os.ReadFile(s.filePath)
+// read replaces Sessions with the file's contents, leaving them as they were
+// if the file is missing or can't be parsed. Callers hold s.mu.
+func (s *Store) read() error {
+	info, err := os.Stat(s.filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
 		}
 		return err
 	}
+	data, err := os.ReadFile(s.filePath)
+	if err != nil {
+		return err
+	}
 
-	return json.Unmarshal(data, s)
+	var disk struct {
+		Sessions []*Session `json:"sessions"`
+	}
+	if err := json.Unmarshal(data, &disk); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", s.filePath, err)
+	}
+	if disk.Sessions == nil {
+		disk.Sessions = make([]*Session, 0)
+	}
+	s.Sessions = disk.Sessions
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// write replaces the file atomically. Callers hold s.mu and the file lock.
+func (s *Store) write() error {
+	if err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := s.filePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.filePath); err != nil {
+		return err
+	}
+	if info, err := os.Stat(s.filePath); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
+
+// Reload re-reads the store if another process changed it since it was last
+// loaded or saved, so the long-running monitor sees sessions and settings
+// written by CLI commands.
+func (s *Store) Reload() error {
+	info, err := os.Stat(s.filePath)
+	if err != nil {
+		return nil
+	}
+
+	s.mu.RLock()
+	changed := !info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if !changed {
+		return nil
+	}
+	return s.load()
 }
 
+// Save writes the sessions as they are in memory
 func (s *Store) Save() error {
+	if err := config.EnsureConfigDir(); err != nil {
+		return err
+	}
+	unlock, err := filelock.Lock(s.filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.write()
+}
 
+// update re-reads the file under the file lock, applies fn and writes the
+// result, so changes made by other processes since the last load survive.
+func (s *Store) update(fn func() error) error {
 	if err := config.EnsureConfigDir(); err != nil {
 		return err
 	}
-
-	data, err := json.MarshalIndent(s, "", "  ")
+	unlock, err := filelock.Lock(s.filePath)
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return os.WriteFile(s.filePath, data, 0644)
+	if err := s.read(); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	return s.write()
 }
 
 func (s *Store) Add(session *Session) error {
-	s.mu.Lock()
-	s.Sessions = append(s.Sessions, session)
-	s.mu.Unlock()
-	return s.Save()
+	return s.update(func() error {
+		s.Sessions = append(s.Sessions, session)
+		return nil
+	})
 }
 
 func (s *Store) Remove(id string) error {
-	s.mu.Lock()
-	for i, sess := range s.Sessions {
-		if sess.ID == id {
-			s.Sessions = append(s.Sessions[:i], s.Sessions[i+1:]...)
-			break
+	return s.update(func() error {
+		for i, sess := range s.Sessions {
+			if sess.ID == id {
+				s.Sessions = append(s.Sessions[:i], s.Sessions[i+1:]...)
+				break
+			}
 		}
-	}
-	s.mu.Unlock()
-	return s.Save()
+		return nil
+	})
 }
 
 func (s *Store) FindByBranch(branch string) *Session {
@@ -115,20 +205,36 @@ func (s *Store) GetAll() []*Session {
 }
 
 func (s *Store) Update(session *Session) error {
-	s.mu.Lock()
-	for i, sess := range s.Sessions {
-		if sess.ID == session.ID {
-			s.Sessions[i] = session
-			break
+	return s.update(func() error {
+		for i, sess := range s.Sessions {
+			if sess.ID == session.ID {
+				s.Sessions[i] = session
+				break
+			}
 		}
-	}
-	s.mu.Unlock()
-	return s.Save()
+		return nil
+	})
+}
+
+// Modify applies fn to a copy of the stored session, as currently on disk,
+// and replaces it, so callers only change the fields they touch.
+func (s *Store) Modify(id string, fn func(sess *Session)) error {
+	return s.update(func() error {
+		for i, sess := range s.Sessions {
+			if sess.ID == id {
+				c := *sess
+				fn(&c)
+				s.Sessions[i] = &c
+				return nil
+			}
+		}
+		return fmt.Errorf("session %s not found", id)
+	})
 }
 
 func (s *Store) Clear() error {
-	s.mu.Lock()
-	s.Sessions = make([]*Session, 0)
-	s.mu.Unlock()
-	return s.Save()
+	return s.update(func() error {
+		s.Sessions = make([]*Session, 0)
+		return nil
+	})
 }
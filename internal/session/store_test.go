@@ -0,0 +1,83 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bb/gclaude/internal/config"
+)
+
+// openStore loads the store at the test's config dir, as a separate process would
+func openStore(t *testing.T) *Store {
+	t.Helper()
+	s := &Store{Sessions: make([]*Session, 0), filePath: filepath.Join(config.GetConfigDir(), "sessions.json")}
+	if err := s.load(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "gclaude-store")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestModifyKeepsOtherProcessChanges(t *testing.T) {
+	cli := openStore(t)
+	if err := cli.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.Add(&Session{ID: "a", Branch: "feat", Status: StatusRunning}); err != nil {
+		t.Fatal(err)
+	}
+
+	monitor := openStore(t)
+
+	// 'gclaude mute' runs while the monitor holds its earlier copy
+	if err := cli.Modify("a", func(s *Session) { s.Muted = true; s.Priority = PriorityHigh }); err != nil {
+		t.Fatal(err)
+	}
+	if err := monitor.Modify("a", func(s *Session) { s.Status = StatusWaitingInput }); err != nil {
+		t.Fatal(err)
+	}
+
+	got := openStore(t).FindByID("a")
+	if got == nil {
+		t.Fatal("session lost")
+	}
+	if !got.Muted || got.Priority != PriorityHigh || got.Status != StatusWaitingInput {
+		t.Errorf("session = %+v, want mute and priority from the CLI and status from the monitor", got)
+	}
+}
+
+func TestReloadKeepsSessionsOnParseError(t *testing.T) {
+	s := openStore(t)
+	if err := s.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Add(&Session{ID: "a", Branch: "feat"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(s.filePath, []byte(`{"sessions": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err == nil {
+		t.Error("Reload() of a truncated file succeeded")
+	}
+	if len(s.GetAll()) != 1 {
+		t.Errorf("Reload() dropped sessions after a failed parse")
+	}
+
+	// And a write doesn't replace the unreadable file with partial state
+	if err := s.Modify("a", func(s *Session) { s.Muted = true }); err == nil {
+		t.Error("Modify() over an unparseable file succeeded")
+	}
+}
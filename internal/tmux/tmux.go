@@ -2,6 +2,7 @@ package tmux
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -35,6 +36,8 @@ func CreateSession(name, workDir, command string) error {
 
 	// Enable mouse support for scrolling
 	SetOption(name, "mouse", "on")
+	// Keep the pane around when the agent exits so its exit status can be read
+	SetOption(name, "remain-on-exit", "on")
 
 	return nil
 }
@@ -57,9 +60,19 @@ func AttachSession(name string) error {
 	return cmd.Run()
 }
 
+// QueryTimeout bounds tmux commands issued by the monitor so a wedged tmux
+// server can't stall a whole polling tick.
+var QueryTimeout = 2 * time.Second
+
+func queryCommand(args ...string) (*exec.Cmd, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), QueryTimeout)
+	return exec.CommandContext(ctx, "tmux", args...), cancel
+}
+
 func CapturePane(sessionName string, lines int) (string, error) {
 	startLine := fmt.Sprintf("-%d", lines)
-	cmd := exec.Command("tmux", "capture-pane", "-t", sessionName, "-p", "-S", startLine)
+	cmd, cancel := queryCommand("capture-pane", "-t", sessionName, "-p", "-S", startLine)
+	defer cancel()
 	var out bytes.Buffer
 	cmd.Stdout = &out
 	if err := cmd.Run(); err != nil {
@@ -73,6 +86,12 @@ func SendKeys(sessionName, keys string) error {
 	return cmd.Run()
 }
 
+// SendKey sends a single key (e.g. "Escape") without pressing Enter
+func SendKey(sessionName, key string) error {
+	cmd := exec.Command("tmux", "send-keys", "-t", sessionName, key)
+	return cmd.Run()
+}
+
 func ListSessions() ([]string, error) {
 	cmd := exec.Command("tmux", "list-sessions", "-F", "#{session_name}")
 	var out bytes.Buffer
@@ -107,6 +126,71 @@ func GetPanePid(sessionName string) (string, error) {
 	return strings.TrimSpace(out.String()), nil
 }
 
+// PaneStatus describes the process running in a session's active pane
+type PaneStatus struct {
+	Dead           bool
+	ExitCode       int
+	CurrentCommand string
+	Pid            string
+}
+
+// PaneInfo is the state of a session's active pane as reported by ListPanes
+type PaneInfo struct {
+	PaneStatus
+	Attached bool
+}
+
+const paneFormat = "#{session_name}\t#{window_active}#{pane_active}\t#{session_attached}\t#{pane_dead}\t#{pane_dead_status}\t#{pane_pid}\t#{pane_current_command}"
+
+// ListPanes returns the active pane of every tmux session, keyed by session
+// name, in a single tmux call.
+func ListPanes() (map[string]*PaneInfo, error) {
+	cmd, cancel := queryCommand("list-panes", "-a", "-F", paneFormat)
+	defer cancel()
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			// No server running
+			return map[string]*PaneInfo{}, nil
+		}
+		return nil, err
+	}
+
+	panes := make(map[string]*PaneInfo)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		fields := strings.SplitN(line, "\t", 7)
+		// Only the active pane of the active window
+		if len(fields) < 6 || fields[1] != "11" {
+			continue
+		}
+		info := &PaneInfo{
+			PaneStatus: PaneStatus{
+				Dead: fields[3] == "1",
+				Pid:  fields[5],
+			},
+			Attached: fields[2] != "" && fields[2] != "0",
+		}
+		if fields[4] != "" {
+			fmt.Sscanf(fields[4], "%d", &info.ExitCode)
+		}
+		if len(fields) == 7 {
+			info.CurrentCommand = fields[6]
+		}
+		panes[fields[0]] = info
+	}
+	return panes, nil
+}
+
+func RespawnPane(sessionName, workDir, command string) error {
+	args := []string{"respawn-pane", "-k", "-t", sessionName, "-c", workDir}
+	if command != "" {
+		args = append(args, command)
+	}
+	cmd := exec.Command("tmux", args...)
+	return cmd.Run()
+}
+
 func IsSessionAttached(sessionName string) bool {
 	cmd := exec.Command("tmux", "list-clients", "-t", sessionName, "-F", "#{client_tty}")
 	var out bytes.Buffer
@@ -128,6 +212,60 @@ func GetAttachedClientTTY(sessionName string) string {
 	return strings.TrimSpace(out.String())
 }
 
+// Client is a tmux client attached to a session
+type Client struct {
+	PID     string
+	TTY     string
+	Session string
+}
+
+// ListClients returns the clients attached to a session, or all clients when
+// sessionName is empty
+func ListClients(sessionName string) ([]Client, error) {
+	args := []string{"list-clients", "-F", "#{client_pid}\t#{client_tty}\t#{client_session}"}
+	if sessionName != "" {
+		args = append(args, "-t", sessionName)
+	}
+	cmd := exec.Command("tmux", args...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	var clients []Client
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) == 3 {
+			clients = append(clients, Client{PID: fields[0], TTY: fields[1], Session: fields[2]})
+		}
+	}
+	return clients, nil
+}
+
+// DisplayMessage shows msg in the status line of a client for duration
+func DisplayMessage(clientTTY, msg string, duration time.Duration) error {
+	args := []string{"display-message", "-c", clientTTY}
+	if duration > 0 {
+		args = append(args, "-d", fmt.Sprintf("%d", duration.Milliseconds()))
+	}
+	// Escape tmux format characters
+	args = append(args, strings.ReplaceAll(msg, "#", "##"))
+	cmd := exec.Command("tmux", args...)
+	return cmd.Run()
+}
+
+// DisplayPopup opens a popup on a client running command. It doesn't wait
+// for the popup to be closed.
+func DisplayPopup(clientTTY, title, command string) error {
+	cmd := exec.Command("tmux", "display-popup", "-c", clientTTY, "-T", title, "-E", command)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
+
 // GetClientLastActivity returns seconds since last client input activity
 func GetClientLastActivity(sessionName string) int {
 	// Get client_activity (Unix timestamp of last activity)
@@ -0,0 +1,172 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/bb/gclaude/internal/config"
+)
+
+// Strategies for where new worktrees go
+const (
+	StrategySibling = "sibling"
+	StrategyInRepo  = "in-repo"
+	StrategyCentral = "central"
+	StrategyCustom  = "custom"
+)
+
+// inRepoDir holds worktrees for the in-repo strategy
+const inRepoDir = ".gclaude/worktrees"
+
+// ValidateStrategy checks a worktree.strategy value
+func ValidateStrategy(strategy string) error {
+	switch strategy {
+	case StrategySibling, StrategyInRepo, StrategyCentral, StrategyCustom:
+		return nil
+	}
+	return fmt.Errorf("invalid worktree strategy %q (want sibling, in-repo, central or custom)", strategy)
+}
+
+// pathVars are available to custom location templates
+type pathVars struct {
+	Repo     string
+	RepoRoot string
+	Branch   string
+	Home     string
+}
+
+// GetWorktreePath returns where the worktree for branch goes under the
+// configured strategy
+func GetWorktreePath(repoRoot, branch string) (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", err
+	}
+	return worktreePath(cfg.Worktree, repoRoot, branch)
+}
+
+func worktreePath(cfg config.WorktreeConfig, repoRoot, branch string) (string, error) {
+	name := sanitizeBranch(branch)
+	repo := filepath.Base(repoRoot)
+
+	switch cfg.Strategy {
+	case "", StrategySibling:
+		return filepath.Join(filepath.Dir(repoRoot), repo+"-worktrees", name), nil
+	case StrategyInRepo:
+		return filepath.Join(repoRoot, inRepoDir, name), nil
+	case StrategyCentral:
+		root := cfg.Root
+		if root == "" {
+			root = "~/worktrees"
+		}
+		return filepath.Join(expandHome(root), repo, name), nil
+	case StrategyCustom:
+		if cfg.Template == "" {
+			return "", fmt.Errorf("worktree.template is required for the custom strategy")
+		}
+		tmpl, err := template.New("worktree").Parse(cfg.Template)
+		if err != nil {
+			return "", fmt.Errorf("invalid worktree template: %w", err)
+		}
+		home, _ := os.UserHomeDir()
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, pathVars{Repo: repo, RepoRoot: repoRoot, Branch: name, Home: home}); err != nil {
+			return "", fmt.Errorf("invalid worktree template: %w", err)
+		}
+		path := expandHome(strings.TrimSpace(buf.String()))
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(repoRoot, path)
+		}
+		return filepath.Clean(path), nil
+	}
+	return "", ValidateStrategy(cfg.Strategy)
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// prepareLocation creates the parent of a worktree at path and, when path is
+// inside the repository, keeps it out of 'git status' via .git/info/exclude
+func prepareLocation(repoRoot, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	rel, err := filepath.Rel(repoRoot, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return nil
+	}
+	top := strings.Split(filepath.ToSlash(rel), "/")[0]
+	return addExclude(repoRoot, "/"+top+"/")
+}
+
+// addExclude appends pattern to the repository's info/exclude, once
+func addExclude(repoRoot, pattern string) error {
+	cmd := exec.Command("git", "-C", repoRoot, "rev-parse", "--git-common-dir")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to find git directory: %w", err)
+	}
+	gitDir := strings.TrimSpace(out.String())
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoRoot, gitDir)
+	}
+
+	excludePath := filepath.Join(gitDir, "info", "exclude")
+	data, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if len(data) > 0 && !bytes.HasSuffix(data, []byte("\n")) {
+		pattern = "\n" + pattern
+	}
+	_, err = fmt.Fprintf(f, "%s\n", pattern)
+	return err
+}
+
+// Move relocates the worktree at from to to with 'git worktree move'
+func Move(repoRoot, from, to string) error {
+	if err := prepareLocation(repoRoot, to); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", "-C", repoRoot, "worktree", "move", from, to)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to move worktree: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	// Drop the old parent (e.g. <repo>-worktrees) once it's empty
+	os.Remove(filepath.Dir(from))
+	return nil
+}
@@ -3,7 +3,6 @@ package worktree
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
@@ -19,15 +18,6 @@ func GetRepoRoot(path string) (string, error) {
 	return strings.TrimSpace(out.String()), nil
 }
 
-func GetWorktreeDir(repoRoot string) string {
-	repoName := filepath.Base(repoRoot)
-	return filepath.Join(filepath.Dir(repoRoot), repoName+"-worktrees")
-}
-
-func GetWorktreePath(repoRoot, branch string) string {
-	return filepath.Join(GetWorktreeDir(repoRoot), sanitizeBranch(branch))
-}
-
 func sanitizeBranch(branch string) string {
 	result := make([]byte, 0, len(branch))
 	for i := 0; i < len(branch); i++ {
@@ -41,10 +31,20 @@ func sanitizeBranch(branch string) string {
 	return string(result)
 }
 
-func Exists(repoRoot, branch string) bool {
-	path := GetWorktreePath(repoRoot, branch)
-	info, err := os.Stat(path)
-	return err == nil && info.IsDir()
+// Find returns the path of the linked worktree that has branch checked out,
+// wherever it was created, or "" if there is none
+func Find(repoRoot, branch string) string {
+	worktrees, err := List(repoRoot)
+	if err != nil {
+		return ""
+	}
+	// The first entry is the main worktree
+	for i, wt := range worktrees {
+		if i > 0 && wt.Branch == branch {
+			return wt.Path
+		}
+	}
+	return ""
 }
 
 func BranchExists(repoRoot, branch string) (bool, error) {
@@ -61,12 +61,13 @@ func BranchExists(repoRoot, branch string) (bool, error) {
 }
 
 func Create(repoRoot, branch string) (string, error) {
-	worktreeDir := GetWorktreeDir(repoRoot)
-	if err := os.MkdirAll(worktreeDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create worktree directory: %w", err)
+	worktreePath, err := GetWorktreePath(repoRoot, branch)
+	if err != nil {
+		return "", err
+	}
+	if err := prepareLocation(repoRoot, worktreePath); err != nil {
+		return "", err
 	}
-
-	worktreePath := GetWorktreePath(repoRoot, branch)
 
 	exists, err := BranchExists(repoRoot, branch)
 	if err != nil {
@@ -89,9 +90,7 @@ func Create(repoRoot, branch string) (string, error) {
 	return worktreePath, nil
 }
 
-func Remove(repoRoot, branch string) error {
-	worktreePath := GetWorktreePath(repoRoot, branch)
-
+func Remove(repoRoot, worktreePath string) error {
 	cmd := exec.Command("git", "-C", repoRoot, "worktree", "remove", worktreePath, "--force")
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
@@ -102,7 +101,15 @@ func Remove(repoRoot, branch string) error {
 	return nil
 }
 
-func List(repoRoot string) ([]string, error) {
+// Worktree is an entry of 'git worktree list'
+type Worktree struct {
+	Path string
+	// Branch is empty for a detached HEAD
+	Branch string
+}
+
+// List returns the repository's worktrees, the main worktree first
+func List(repoRoot string) ([]Worktree, error) {
 	cmd := exec.Command("git", "-C", repoRoot, "worktree", "list", "--porcelain")
 	var out bytes.Buffer
 	cmd.Stdout = &out
@@ -110,13 +117,16 @@ func List(repoRoot string) ([]string, error) {
 		return nil, err
 	}
 
-	var paths []string
+	var worktrees []Worktree
 	for _, line := range strings.Split(out.String(), "\n") {
-		if strings.HasPrefix(line, "worktree ") {
-			paths = append(paths, strings.TrimPrefix(line, "worktree "))
+		switch {
+		case strings.HasPrefix(line, "worktree "):
+			worktrees = append(worktrees, Worktree{Path: strings.TrimPrefix(line, "worktree ")})
+		case strings.HasPrefix(line, "branch ") && len(worktrees) > 0:
+			worktrees[len(worktrees)-1].Branch = strings.TrimPrefix(line, "branch refs/heads/")
 		}
 	}
-	return paths, nil
+	return worktrees, nil
 }
 
 func IsMainRepo(path string) (bool, error) {